@@ -0,0 +1,150 @@
+package aid
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Application identifiers of the built-in schemes.
+const (
+	PromptPayAnyID       = "A000000677010111"
+	PromptPayBillPayment = "A000000677010112"
+	PromptPayCrossBorder = "A000000677010113"
+	Visa                 = "A0000000031010"
+	Mastercard           = "A0000000041010"
+	Discover             = "A0000001523010"
+	AmericanExpress      = "A00000002501"
+	JCB                  = "A0000000651010"
+	UnionPay             = "A000000333010101"
+)
+
+// Scheme describes a payment scheme that can appear in a merchant account.
+type Scheme struct {
+	// AID is the application identifier (or other GUID) of the scheme
+	AID string
+
+	// Name is the display name
+	Name string
+
+	// Tags are the merchant account tags of the scheme, preferred first.
+	// Card schemes list their EMVCo-reserved primitive tags (02-25);
+	// template schemes list their usual template tags (26-51).
+	Tags []string
+}
+
+// Primitive reports whether the scheme uses primitive tags (02-25), whose
+// value is a merchant ID rather than a template with the AID in sub-tag 00.
+func (s Scheme) Primitive() bool {
+	return len(s.Tags) > 0 && isPrimitiveTag(s.Tags[0])
+}
+
+var builtin = []Scheme{
+	{AID: PromptPayAnyID, Name: "PromptPay", Tags: []string{"29"}},
+	{AID: PromptPayBillPayment, Name: "PromptPay Bill Payment", Tags: []string{"30"}},
+	{AID: PromptPayCrossBorder, Name: "PromptPay Cross-Border", Tags: []string{"30", "31"}},
+	{AID: Visa, Name: "Visa", Tags: []string{"02", "03"}},
+	{AID: Mastercard, Name: "Mastercard", Tags: []string{"04", "05"}},
+	{AID: Discover, Name: "Discover", Tags: []string{"09", "10"}},
+	{AID: AmericanExpress, Name: "American Express", Tags: []string{"11", "12"}},
+	{AID: JCB, Name: "JCB", Tags: []string{"13", "14"}},
+	{AID: UnionPay, Name: "UnionPay", Tags: []string{"15", "16"}},
+}
+
+var (
+	mu       sync.RWMutex
+	registry = indexByAID(builtin)
+	byTag    = indexByTag(builtin)
+)
+
+func indexByAID(schemes []Scheme) map[string]Scheme {
+	m := make(map[string]Scheme, len(schemes))
+	for _, s := range schemes {
+		m[s.AID] = s
+	}
+	return m
+}
+
+func indexByTag(schemes []Scheme) map[string]Scheme {
+	m := make(map[string]Scheme)
+	for _, s := range schemes {
+		if s.Primitive() {
+			for _, tag := range s.Tags {
+				m[tag] = s
+			}
+		}
+	}
+	return m
+}
+
+// Lookup returns the scheme with the given AID.
+func Lookup(aid string) (Scheme, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := registry[aid]
+	return s, ok
+}
+
+// LookupTag returns the card scheme that owns a primitive tag (02-25).
+func LookupTag(tagID string) (Scheme, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	s, ok := byTag[tagID]
+	return s, ok
+}
+
+// All returns every registered scheme, sorted by AID.
+func All() []Scheme {
+	mu.RLock()
+	defer mu.RUnlock()
+	schemes := make([]Scheme, 0, len(registry))
+	for _, s := range registry {
+		schemes = append(schemes, s)
+	}
+	sort.Slice(schemes, func(i, j int) bool { return schemes[i].AID < schemes[j].AID })
+	return schemes
+}
+
+// Register adds a scheme to the registry, or replaces the scheme with the same AID.
+//
+// Returns an error if the AID is empty or longer than 32 characters, or if the
+// tags are not all primitive (02-25) or all templates (26-51).
+func Register(s Scheme) error {
+	if s.AID == "" || len(s.AID) > 32 {
+		return fmt.Errorf("invalid scheme AID %q: must be 1-32 characters", s.AID)
+	}
+	if len(s.Tags) == 0 {
+		return errors.New("invalid scheme " + s.AID + ": no tags")
+	}
+	for _, tag := range s.Tags {
+		if !isAccountTag(tag) || isPrimitiveTag(tag) != s.Primitive() {
+			return fmt.Errorf("invalid scheme %s tag %q: tags must be all 02-25 or all 26-51", s.AID, tag)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if old, ok := registry[s.AID]; ok && old.Primitive() {
+		for _, tag := range old.Tags {
+			delete(byTag, tag)
+		}
+	}
+	registry[s.AID] = s
+	if s.Primitive() {
+		for _, tag := range s.Tags {
+			byTag[tag] = s
+		}
+	}
+	return nil
+}
+
+// isAccountTag reports whether id is a merchant account tag (02-51).
+func isAccountTag(id string) bool {
+	return len(id) == 2 && id[0] >= '0' && id[0] <= '9' && id[1] >= '0' && id[1] <= '9' && id >= "02" && id <= "51"
+}
+
+// isPrimitiveTag reports whether id is a tag reserved for card schemes (02-25).
+func isPrimitiveTag(id string) bool {
+	return id >= "02" && id <= "25"
+}
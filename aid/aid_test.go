@@ -0,0 +1,70 @@
+package aid
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		aid       string
+		name      string
+		primitive bool
+	}{
+		{PromptPayAnyID, "PromptPay", false},
+		{PromptPayBillPayment, "PromptPay Bill Payment", false},
+		{Visa, "Visa", true},
+		{UnionPay, "UnionPay", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, ok := Lookup(tt.aid)
+			if !ok {
+				t.Fatalf("Lookup(%q) ok = false", tt.aid)
+			}
+			if s.Name != tt.name {
+				t.Errorf("Lookup(%q).Name = %v, want %v", tt.aid, s.Name, tt.name)
+			}
+			if s.Primitive() != tt.primitive {
+				t.Errorf("Lookup(%q).Primitive() = %v, want %v", tt.aid, s.Primitive(), tt.primitive)
+			}
+		})
+	}
+
+	if _, ok := Lookup("A000000000"); ok {
+		t.Error("Lookup(unknown) ok = true, want false")
+	}
+}
+
+func TestLookupTag(t *testing.T) {
+	if s, ok := LookupTag("05"); !ok || s.AID != Mastercard {
+		t.Errorf("LookupTag(05) = %v, %v, want Mastercard", s, ok)
+	}
+	if _, ok := LookupTag("29"); ok {
+		t.Error("LookupTag(29) ok = true, want false for a template tag")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	scheme := Scheme{AID: "D76400000001", Name: "Test Wallet", Tags: []string{"40"}}
+	if err := Register(scheme); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if s, ok := Lookup(scheme.AID); !ok || s.Name != "Test Wallet" {
+		t.Errorf("Lookup() after Register() = %v, %v", s, ok)
+	}
+
+	invalid := []Scheme{
+		{AID: "", Tags: []string{"40"}},
+		{AID: "D76400000002"},
+		{AID: "D76400000003", Tags: []string{"52"}},
+		{AID: "D76400000004", Tags: []string{"20", "40"}},
+	}
+	for _, s := range invalid {
+		if err := Register(s); err == nil {
+			t.Errorf("Register(%+v) error = nil, want error", s)
+		}
+	}
+
+	if len(All()) < len(builtin) {
+		t.Errorf("All() = %d schemes, want at least %d", len(All()), len(builtin))
+	}
+}
@@ -0,0 +1,8 @@
+// Package aid provides a registry of payment schemes and their application
+// identifiers (AIDs) for EMVCo merchant-presented QR codes.
+//
+// A QR code can carry merchant accounts of several schemes at once. Card schemes
+// use primitive tags reserved by EMVCo (02-25, e.g. 02-03 for Visa) holding a
+// merchant ID; other schemes use templates (26-51) that start with their AID
+// in sub-tag 00, e.g. PromptPay AnyID in Tag 29.
+package aid
@@ -0,0 +1,207 @@
+package thaiqrgo
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// THBMinorUnits is the number of minor unit digits of the Thai baht (1 baht = 100 satang).
+const THBMinorUnits = 2
+
+// maxAmountLength is the maximum length of the transaction amount (Tag 54).
+const maxAmountLength = 13
+
+// Amount is an exact, non-negative monetary amount.
+//
+// It is stored as an integer number of minor units (satang for THB) together
+// with the number of minor unit digits of the currency, so that values like
+// 19.99 are never rounded. The zero value is an amount of 0 with no minor units.
+type Amount struct {
+	minor int64
+	units int
+}
+
+// NewAmount returns an amount of minor units for a currency with minorUnits decimal digits.
+//
+// Returns an error if the amount is negative or does not fit in the 13-character
+// transaction amount field.
+func NewAmount(minor int64, minorUnits int) (Amount, error) {
+	if minor < 0 {
+		return Amount{}, fmt.Errorf("invalid amount: %d is negative", minor)
+	}
+	if minorUnits < 0 || minorUnits > 4 {
+		return Amount{}, fmt.Errorf("invalid amount: unsupported number of minor units %d", minorUnits)
+	}
+
+	a := Amount{minor: minor, units: minorUnits}
+	if s := a.String(); len(s) > maxAmountLength {
+		return Amount{}, fmt.Errorf("invalid amount: %s exceeds %d characters", s, maxAmountLength)
+	}
+	return a, nil
+}
+
+// Satang returns a THB amount of the given number of satang.
+//
+// Returns an error if the amount is negative or too large.
+func Satang(satang int64) (Amount, error) {
+	return NewAmount(satang, THBMinorUnits)
+}
+
+// ParseAmount parses a THB amount in decimal notation (e.g. "100", "19.99"),
+// as used by the transaction amount field (Tag 54).
+func ParseAmount(s string) (Amount, error) {
+	return ParseAmountUnits(s, THBMinorUnits)
+}
+
+// ParseAmountUnits parses an amount in decimal notation for a currency with
+// minorUnits decimal digits.
+//
+// Signs, exponents, NaN, infinities and more decimals than the currency allows are rejected.
+func ParseAmountUnits(s string, minorUnits int) (Amount, error) {
+	whole, fraction, _ := strings.Cut(s, ".")
+	if whole == "" && fraction == "" {
+		return Amount{}, fmt.Errorf("invalid amount format: %q", s)
+	}
+	if !isDigits(whole) || !isDigits(fraction) {
+		return Amount{}, fmt.Errorf("invalid amount format: %q", s)
+	}
+	if len(fraction) > minorUnits {
+		return Amount{}, fmt.Errorf("invalid amount format: %q has more than %d decimals", s, minorUnits)
+	}
+	if len(s) > maxAmountLength {
+		return Amount{}, fmt.Errorf("invalid amount: %s exceeds %d characters", s, maxAmountLength)
+	}
+
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", minorUnits-len(fraction)), "0")
+	if digits == "" {
+		return NewAmount(0, minorUnits)
+	}
+	minor, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount format: %w", err)
+	}
+	return NewAmount(minor, minorUnits)
+}
+
+// ParseBarcodeAmount parses a THB amount in satang, as used by BOT Barcodes (e.g. "364922").
+func ParseBarcodeAmount(s string) (Amount, error) {
+	if !isDigits(s) || s == "" {
+		return Amount{}, fmt.Errorf("invalid amount format: %q", s)
+	}
+	minor, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return Amount{}, fmt.Errorf("invalid amount format: %w", err)
+	}
+	return Satang(minor)
+}
+
+// AmountFromFloat converts a THB amount given as a float to an exact amount,
+// rounding to the nearest satang.
+//
+// Returns an error for negative values, NaN and infinities.
+func AmountFromFloat(f float64) (Amount, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Amount{}, errors.New("invalid amount: not a finite number")
+	}
+	if f < 0 {
+		return Amount{}, fmt.Errorf("invalid amount: %v is negative", f)
+	}
+	return ParseAmount(strconv.FormatFloat(f, 'f', THBMinorUnits, 64))
+}
+
+// MustParseAmount is like ParseAmount but panics if the amount cannot be parsed.
+//
+// It simplifies the initialization of constant amounts.
+func MustParseAmount(s string) Amount {
+	a, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Minor returns the amount in minor units (satang for THB).
+func (a Amount) Minor() int64 {
+	return a.minor
+}
+
+// MinorUnits returns the number of minor unit digits of the currency.
+func (a Amount) MinorUnits() int {
+	return a.units
+}
+
+// IsZero reports whether the amount is zero.
+func (a Amount) IsZero() bool {
+	return a.minor == 0
+}
+
+// Float64 returns the amount as a float, for display purposes only.
+func (a Amount) Float64() float64 {
+	return float64(a.minor) / math.Pow10(a.units)
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Returns the amount in decimal notation with all minor unit digits (e.g. "19.99"),
+// as used by the transaction amount field (Tag 54).
+func (a Amount) String() string {
+	s := strconv.FormatInt(a.minor, 10)
+	if a.units == 0 {
+		return s
+	}
+	if len(s) <= a.units {
+		s = strings.Repeat("0", a.units-len(s)+1) + s
+	}
+	return s[:len(s)-a.units] + "." + s[len(s)-a.units:]
+}
+
+// BarcodeString returns the amount in satang, as used by BOT Barcodes (e.g. "1999").
+//
+// Amounts with fewer minor units are scaled up (150 with no minor units is "15000");
+// digits beyond satang are dropped.
+func (a Amount) BarcodeString() string {
+	minor := a.minor
+	for units := a.units; units < THBMinorUnits; units++ {
+		minor *= 10
+	}
+	for units := a.units; units > THBMinorUnits; units-- {
+		minor /= 10
+	}
+	return strconv.FormatInt(minor, 10)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+//
+// The amount is encoded as a JSON number with all minor unit digits (e.g. 19.99).
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+//
+// It accepts a THB amount as a JSON number or string.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	s := strings.Trim(string(data), `"`)
+	parsed, err := ParseAmount(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
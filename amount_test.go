@@ -0,0 +1,164 @@
+package thaiqrgo
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		minor   int64
+		wantErr bool
+	}{
+		{input: "19.99", want: "19.99", minor: 1999},
+		{input: "30", want: "30.00", minor: 3000},
+		{input: "4.2", want: "4.20", minor: 420},
+		{input: ".5", want: "0.50", minor: 50},
+		{input: "0", want: "0.00", minor: 0},
+		{input: "0000000001.00", want: "1.00", minor: 100},
+		{input: "9999999999.99", want: "9999999999.99", minor: 999999999999},
+		{input: "", wantErr: true},
+		{input: ".", wantErr: true},
+		{input: "-1.00", wantErr: true},
+		{input: "+1.00", wantErr: true},
+		{input: "1e3", wantErr: true},
+		{input: "NaN", wantErr: true},
+		{input: "1.999", wantErr: true},
+		{input: "1,000.00", wantErr: true},
+		{input: "99999999999.99", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseAmount(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAmount(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseAmount(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+			if got.Minor() != tt.minor {
+				t.Errorf("ParseAmount(%q).Minor() = %v, want %v", tt.input, got.Minor(), tt.minor)
+			}
+		})
+	}
+}
+
+func TestAmountFromFloat(t *testing.T) {
+	tests := []struct {
+		input   float64
+		minor   int64
+		wantErr bool
+	}{
+		{input: 19.99, minor: 1999},
+		{input: 0.1 + 0.2, minor: 30},
+		{input: 3649.22, minor: 364922},
+		{input: -1, wantErr: true},
+		{input: math.NaN(), wantErr: true},
+		{input: math.Inf(1), wantErr: true},
+		{input: 1e20, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := AmountFromFloat(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("AmountFromFloat(%v) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got.Minor() != tt.minor {
+			t.Errorf("AmountFromFloat(%v).Minor() = %v, want %v", tt.input, got.Minor(), tt.minor)
+		}
+	}
+}
+
+func TestNewAmount(t *testing.T) {
+	a, err := NewAmount(12345, 3)
+	if err != nil {
+		t.Fatalf("NewAmount() error = %v", err)
+	}
+	if a.String() != "12.345" || a.MinorUnits() != 3 {
+		t.Errorf("NewAmount() = %v (%v), want 12.345 (3)", a, a.MinorUnits())
+	}
+
+	if _, err := NewAmount(-1, 2); err == nil {
+		t.Error("NewAmount() should return error for negative amount")
+	}
+
+	a, err = Satang(5)
+	if err != nil {
+		t.Fatalf("Satang() error = %v", err)
+	}
+	if a.String() != "0.05" {
+		t.Errorf("Satang(5) = %v, want 0.05", a)
+	}
+}
+
+func TestAmount_BarcodeString(t *testing.T) {
+	tests := []struct {
+		minor int64
+		units int
+		want  string
+	}{
+		{1999, 2, "1999"},
+		{150, 0, "15000"},
+		{15, 1, "150"},
+		{12345, 3, "1234"},
+		{0, 0, "0"},
+	}
+	for _, tt := range tests {
+		a, err := NewAmount(tt.minor, tt.units)
+		if err != nil {
+			t.Fatalf("NewAmount(%v, %v) error = %v", tt.minor, tt.units, err)
+		}
+		if got := a.BarcodeString(); got != tt.want {
+			t.Errorf("NewAmount(%v, %v).BarcodeString() = %v, want %v", tt.minor, tt.units, got, tt.want)
+		}
+	}
+}
+
+func TestBOTBarcode_AmountIsExact(t *testing.T) {
+	amount := MustParseAmount("19.99")
+	barcode := &BOTBarcode{BillerID: "099400016550100", Ref1: "1", Amount: &amount}
+	want := "|099400016550100\r1\r\r1999"
+	if got := barcode.String(); got != want {
+		t.Errorf("BOTBarcode.String() = %q, want %q", got, want)
+	}
+
+	parsed, err := BOTBarcodeFromString(want)
+	if err != nil {
+		t.Fatalf("BOTBarcodeFromString() error = %v", err)
+	}
+	if parsed.Amount == nil || *parsed.Amount != amount {
+		t.Errorf("BOTBarcodeFromString() Amount = %v, want %v", parsed.Amount, amount)
+	}
+}
+
+func TestAmount_JSON(t *testing.T) {
+	var v struct {
+		Amount *Amount `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount": 19.99}`), &v); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if v.Amount == nil || v.Amount.Minor() != 1999 {
+		t.Fatalf("json.Unmarshal() Amount = %v, want 19.99", v.Amount)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(data) != `{"amount":19.99}` {
+		t.Errorf("json.Marshal() = %s, want {\"amount\":19.99}", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount": "-5"}`), &v); err == nil {
+		t.Error("json.Unmarshal() should return error for negative amount")
+	}
+}
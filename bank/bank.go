@@ -0,0 +1,300 @@
+package bank
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Bank describes a bank and its account number rules.
+type Bank struct {
+	// Code is the 3-digit bank code (e.g. "004")
+	Code string
+
+	// NameEN is the English name
+	NameEN string
+
+	// NameTH is the Thai name
+	NameTH string
+
+	// ShortName is the common abbreviation (e.g. "KBANK")
+	ShortName string
+
+	// BIC is the 8-character SWIFT BIC, if any (e.g. "KASITHBK")
+	BIC string
+
+	// Active is false for banks that no longer operate, e.g. after a merger.
+	// Their codes still appear on old slips.
+	Active bool
+
+	// AccountLengths are the accepted account number lengths.
+	// Empty means the registry has no account rules for the bank.
+	AccountLengths []int
+
+	// AccountFormat is the display layout of an account number, with one 'x'
+	// per digit (e.g. "xxx-x-xxxxx-x"). Empty means no grouping.
+	AccountFormat string
+
+	// CheckAccount is an optional extra rule, e.g. a check digit, run on
+	// account numbers with a valid length
+	CheckAccount func(account string) error `json:"-"`
+}
+
+// ErrUnknownCode is returned for bank codes that are not in the registry.
+var ErrUnknownCode = errors.New("unknown bank code")
+
+// Account number layouts shared by several banks.
+const (
+	format10 = "xxx-x-xxxxx-x"
+	format12 = "xx-xxxx-xxxx-xx"
+)
+
+// builtin lists Thai banks and specialized financial institutions.
+var builtin = []Bank{
+	{Code: "002", NameEN: "Bangkok Bank", NameTH: "ธนาคารกรุงเทพ", ShortName: "BBL", BIC: "BKKBTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "004", NameEN: "Kasikornbank", NameTH: "ธนาคารกสิกรไทย", ShortName: "KBANK", BIC: "KASITHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "006", NameEN: "Krung Thai Bank", NameTH: "ธนาคารกรุงไทย", ShortName: "KTB", BIC: "KRTHTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "011", NameEN: "TMBThanachart Bank", NameTH: "ธนาคารทหารไทยธนชาต", ShortName: "TTB", BIC: "TMBKTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "014", NameEN: "Siam Commercial Bank", NameTH: "ธนาคารไทยพาณิชย์", ShortName: "SCB", BIC: "SICOTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "017", NameEN: "Citibank", NameTH: "ธนาคารซิตี้แบงก์", ShortName: "CITI", BIC: "CITITHBX", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "020", NameEN: "Standard Chartered Bank (Thai)", NameTH: "ธนาคารสแตนดาร์ดชาร์เตอร์ด (ไทย)", ShortName: "SCBT", BIC: "SCBLTHBX", Active: true, AccountLengths: []int{11}},
+	{Code: "022", NameEN: "CIMB Thai Bank", NameTH: "ธนาคารซีไอเอ็มบี ไทย", ShortName: "CIMBT", BIC: "UBOBTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "024", NameEN: "United Overseas Bank (Thai)", NameTH: "ธนาคารยูโอบี", ShortName: "UOBT", BIC: "UOVBTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "025", NameEN: "Bank of Ayudhya", NameTH: "ธนาคารกรุงศรีอยุธยา", ShortName: "BAY", BIC: "AYUDTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "030", NameEN: "Government Savings Bank", NameTH: "ธนาคารออมสิน", ShortName: "GSB", BIC: "GSBATHBK", Active: true, AccountLengths: []int{12}, AccountFormat: format12},
+	{Code: "031", NameEN: "The Hongkong and Shanghai Banking Corporation", NameTH: "ธนาคารฮ่องกงและเซี่ยงไฮ้แบงกิ้งคอร์ปอเรชั่น", ShortName: "HSBC", BIC: "HSBCTHBK", Active: true},
+	{Code: "033", NameEN: "Government Housing Bank", NameTH: "ธนาคารอาคารสงเคราะห์", ShortName: "GHB", BIC: "GOHUTHB1", Active: true, AccountLengths: []int{12}, AccountFormat: format12},
+	{Code: "034", NameEN: "Bank for Agriculture and Agricultural Cooperatives", NameTH: "ธนาคารเพื่อการเกษตรและสหกรณ์การเกษตร", ShortName: "BAAC", BIC: "BAABTHBK", Active: true, AccountLengths: []int{12}, AccountFormat: format12},
+	{Code: "039", NameEN: "Mizuho Bank", NameTH: "ธนาคารมิซูโฮ", ShortName: "MHCB", BIC: "MHCBTHBK", Active: true},
+	{Code: "065", NameEN: "Thanachart Bank", NameTH: "ธนาคารธนชาต", ShortName: "TBANK", BIC: "THBKTHBK", Active: false, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "066", NameEN: "Islamic Bank of Thailand", NameTH: "ธนาคารอิสลามแห่งประเทศไทย", ShortName: "IBANK", BIC: "TIBTTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "067", NameEN: "Tisco Bank", NameTH: "ธนาคารทิสโก้", ShortName: "TISCO", BIC: "TFPCTHB1", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "069", NameEN: "Kiatnakin Phatra Bank", NameTH: "ธนาคารเกียรตินาคินภัทร", ShortName: "KKP", BIC: "KKPBTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "070", NameEN: "Industrial and Commercial Bank of China (Thai)", NameTH: "ธนาคารไอซีบีซี (ไทย)", ShortName: "ICBCT", BIC: "ICBKTHBK", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "071", NameEN: "Thai Credit Bank", NameTH: "ธนาคารไทยเครดิต", ShortName: "TCRB", BIC: "THCETHB1", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "073", NameEN: "Land and Houses Bank", NameTH: "ธนาคารแลนด์ แอนด์ เฮ้าส์", ShortName: "LHB", BIC: "LAHRTHB2", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+	{Code: "098", NameEN: "Small and Medium Enterprise Development Bank of Thailand", NameTH: "ธนาคารพัฒนาวิสาหกิจขนาดกลางและขนาดย่อมแห่งประเทศไทย", ShortName: "SME", Active: true, AccountLengths: []int{10}, AccountFormat: format10},
+}
+
+var (
+	mu       sync.RWMutex
+	registry = indexByCode(builtin)
+)
+
+func indexByCode(banks []Bank) map[string]Bank {
+	index := make(map[string]Bank, len(banks))
+	for _, b := range banks {
+		index[b.Code] = b
+	}
+	return index
+}
+
+// Lookup returns the bank with the given 3-digit code.
+func Lookup(code string) (Bank, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	b, ok := registry[code]
+	return b, ok
+}
+
+// LookupBIC returns the bank with the given SWIFT BIC.
+//
+// Both 8-character BICs and 11-character BICs with a branch code are accepted.
+// The comparison is case-insensitive.
+func LookupBIC(bic string) (Bank, bool) {
+	if len(bic) == 11 {
+		bic = bic[:8]
+	}
+	return find(func(b Bank) bool { return b.BIC != "" && strings.EqualFold(b.BIC, bic) })
+}
+
+// LookupShortName returns the bank with the given short name (e.g. "kbank").
+// The comparison is case-insensitive.
+func LookupShortName(name string) (Bank, bool) {
+	return find(func(b Bank) bool { return strings.EqualFold(b.ShortName, name) })
+}
+
+// Name returns the bank name for the given code in English, or the code itself
+// if the bank is unknown. Use it to display codes such as a slip's sending bank.
+func Name(code string) string {
+	if b, ok := Lookup(code); ok {
+		return b.NameEN
+	}
+	return code
+}
+
+func find(match func(Bank) bool) (Bank, bool) {
+	for _, b := range All() {
+		if match(b) {
+			return b, true
+		}
+	}
+	return Bank{}, false
+}
+
+// Active returns the registered banks that still operate, sorted by code.
+func Active() []Bank {
+	var banks []Bank
+	for _, b := range All() {
+		if b.Active {
+			banks = append(banks, b)
+		}
+	}
+	return banks
+}
+
+// All returns every registered bank, sorted by code.
+func All() []Bank {
+	mu.RLock()
+	defer mu.RUnlock()
+	banks := make([]Bank, 0, len(registry))
+	for _, b := range registry {
+		banks = append(banks, b)
+	}
+	sort.Slice(banks, func(i, j int) bool { return banks[i].Code < banks[j].Code })
+	return banks
+}
+
+// Register adds a bank to the registry, or replaces the bank with the same code.
+//
+// Returns an error if the code is not 3 digits or an account length is not positive.
+func Register(b Bank) error {
+	if len(b.Code) != 3 || !isDigits(b.Code) {
+		return fmt.Errorf("invalid bank code: %q", b.Code)
+	}
+	for _, n := range b.AccountLengths {
+		if n <= 0 {
+			return fmt.Errorf("invalid bank %s: account length %d", b.Code, n)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	registry[b.Code] = b
+	return nil
+}
+
+// ValidateAccount checks an account number against the bank's rules.
+//
+// The account number must be digits only; use Clean to strip separators first.
+func (b Bank) ValidateAccount(account string) error {
+	if len(b.AccountLengths) == 0 {
+		return fmt.Errorf("invalid %s account number: no account rules for this bank", b.ShortName)
+	}
+	if account == "" || !isDigits(account) {
+		return fmt.Errorf("invalid %s account number: must contain digits only", b.ShortName)
+	}
+
+	valid := false
+	for _, n := range b.AccountLengths {
+		if len(account) == n {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid %s account number: must be %s digits, got %d", b.ShortName, joinLengths(b.AccountLengths), len(account))
+	}
+
+	if b.CheckAccount != nil {
+		return b.CheckAccount(account)
+	}
+	return nil
+}
+
+// FormatAccount formats an account number for display using AccountFormat.
+//
+// Account numbers that do not match the layout are returned unchanged.
+func (b Bank) FormatAccount(account string) string {
+	if b.AccountFormat == "" || strings.Count(b.AccountFormat, "x") != len(account) {
+		return account
+	}
+
+	var result strings.Builder
+	i := 0
+	for _, c := range b.AccountFormat {
+		if c == 'x' {
+			result.WriteByte(account[i])
+			i++
+		} else {
+			result.WriteRune(c)
+		}
+	}
+	return result.String()
+}
+
+// Account is a bank account, as carried by a PromptPay BANKACC proxy.
+type Account struct {
+	// Bank is the bank holding the account
+	Bank Bank
+
+	// Number is the account number (digits only)
+	Number string
+}
+
+// NewAccount validates an account number for the bank with the given code.
+//
+// Spaces and dashes in the account number are ignored.
+// Returns an error if the bank is unknown or the account number is invalid.
+func NewAccount(code, number string) (Account, error) {
+	b, ok := Lookup(code)
+	if !ok {
+		return Account{}, fmt.Errorf("%w: %q", ErrUnknownCode, code)
+	}
+
+	number = Clean(number)
+	if err := b.ValidateAccount(number); err != nil {
+		return Account{}, err
+	}
+	return Account{Bank: b, Number: number}, nil
+}
+
+// ParseProxy parses a PromptPay BANKACC proxy (Tag 29.04): a 3-digit bank code
+// followed by the account number.
+func ParseProxy(proxy string) (Account, error) {
+	if len(proxy) < 4 {
+		return Account{}, errors.New("invalid bank account proxy: too short")
+	}
+	return NewAccount(proxy[:3], proxy[3:])
+}
+
+// Proxy returns the PromptPay BANKACC proxy value (e.g. "0041234567890").
+func (a Account) Proxy() string {
+	return a.Bank.Code + a.Number
+}
+
+// Format returns the account number formatted for display (e.g. "123-4-56789-0").
+func (a Account) Format() string {
+	return a.Bank.FormatAccount(a.Number)
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Returns the bank short name and the formatted account number (e.g. "KBANK 123-4-56789-0").
+func (a Account) String() string {
+	return a.Bank.ShortName + " " + a.Format()
+}
+
+// Clean removes spaces and dashes from an account number.
+func Clean(account string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(account)
+}
+
+func joinLengths(lengths []int) string {
+	parts := make([]string, len(lengths))
+	for i, n := range lengths {
+		parts[i] = fmt.Sprint(n)
+	}
+	return strings.Join(parts, " or ")
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package bank
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	b, ok := Lookup("004")
+	if !ok {
+		t.Fatal("Lookup(004) not found")
+	}
+	if b.ShortName != "KBANK" {
+		t.Errorf("Lookup(004).ShortName = %v, want KBANK", b.ShortName)
+	}
+
+	if _, ok := Lookup("999"); ok {
+		t.Error("Lookup(999) should not be found")
+	}
+
+	banks := All()
+	for i := 1; i < len(banks); i++ {
+		if banks[i-1].Code >= banks[i].Code {
+			t.Fatalf("All() not sorted: %v before %v", banks[i-1].Code, banks[i].Code)
+		}
+	}
+}
+
+func TestLookupBIC(t *testing.T) {
+	for _, bic := range []string{"SICOTHBK", "sicothbk", "SICOTHBKXXX"} {
+		b, ok := LookupBIC(bic)
+		if !ok || b.Code != "014" {
+			t.Errorf("LookupBIC(%q) = %v, %v, want 014", bic, b.Code, ok)
+		}
+	}
+	if _, ok := LookupBIC(""); ok {
+		t.Error("LookupBIC(\"\") should not be found")
+	}
+}
+
+func TestLookupShortName(t *testing.T) {
+	b, ok := LookupShortName("kbank")
+	if !ok || b.Code != "004" || b.NameTH != "ธนาคารกสิกรไทย" {
+		t.Errorf("LookupShortName(kbank) = %+v, %v, want 004", b, ok)
+	}
+	if _, ok := LookupShortName("NOPE"); ok {
+		t.Error("LookupShortName(NOPE) should not be found")
+	}
+}
+
+func TestActive(t *testing.T) {
+	tbank, ok := Lookup("065")
+	if !ok || tbank.Active {
+		t.Fatalf("Lookup(065) = %+v, %v, want inactive bank", tbank, ok)
+	}
+	for _, b := range Active() {
+		if b.Code == "065" {
+			t.Error("Active() should not include inactive banks")
+		}
+	}
+	if len(Active()) >= len(All()) {
+		t.Errorf("Active() = %d banks, want fewer than All() = %d", len(Active()), len(All()))
+	}
+}
+
+func TestName(t *testing.T) {
+	if got := Name("002"); got != "Bangkok Bank" {
+		t.Errorf("Name(002) = %v, want Bangkok Bank", got)
+	}
+	if got := Name("999"); got != "999" {
+		t.Errorf("Name(999) = %v, want 999", got)
+	}
+}
+
+func TestNewAccount(t *testing.T) {
+	tests := []struct {
+		code, number string
+		proxy        string
+		formatted    string
+		wantErr      bool
+	}{
+		{code: "004", number: "1234567890", proxy: "0041234567890", formatted: "123-4-56789-0"},
+		{code: "014", number: "123-4-56789-0", proxy: "0141234567890", formatted: "123-4-56789-0"},
+		{code: "030", number: "020012345678", proxy: "030020012345678", formatted: "02-0012-3456-78"},
+		{code: "004", number: "123456789", wantErr: true},
+		{code: "030", number: "1234567890", wantErr: true},
+		{code: "004", number: "12345678AB", wantErr: true},
+		{code: "999", number: "1234567890", wantErr: true},
+		{code: "031", number: "123456789012", wantErr: true}, // no account rules
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code+"/"+tt.number, func(t *testing.T) {
+			account, err := NewAccount(tt.code, tt.number)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := account.Proxy(); got != tt.proxy {
+				t.Errorf("Account.Proxy() = %v, want %v", got, tt.proxy)
+			}
+			if got := account.Format(); got != tt.formatted {
+				t.Errorf("Account.Format() = %v, want %v", got, tt.formatted)
+			}
+		})
+	}
+}
+
+func TestParseProxy(t *testing.T) {
+	account, err := ParseProxy("0041234567890")
+	if err != nil {
+		t.Fatalf("ParseProxy() error = %v", err)
+	}
+	if account.Bank.Code != "004" || account.Number != "1234567890" {
+		t.Errorf("ParseProxy() = %v/%v, want 004/1234567890", account.Bank.Code, account.Number)
+	}
+	if got := account.String(); got != "KBANK 123-4-56789-0" {
+		t.Errorf("Account.String() = %v, want KBANK 123-4-56789-0", got)
+	}
+
+	if _, err := ParseProxy("9991234567890"); !errors.Is(err, ErrUnknownCode) {
+		t.Errorf("ParseProxy() error = %v, want %v", err, ErrUnknownCode)
+	}
+	for _, proxy := range []string{"", "004", "00412345"} {
+		if _, err := ParseProxy(proxy); err == nil {
+			t.Errorf("ParseProxy(%q) should return error", proxy)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	errCheck := errors.New("bad check digit")
+	err := Register(Bank{
+		Code:           "900",
+		NameEN:         "Test Bank",
+		ShortName:      "TEST",
+		AccountLengths: []int{8},
+		CheckAccount: func(account string) error {
+			if account[7] != '0' {
+				return errCheck
+			}
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	defer func() {
+		mu.Lock()
+		delete(registry, "900")
+		mu.Unlock()
+	}()
+
+	if _, err := NewAccount("900", "12345670"); err != nil {
+		t.Errorf("NewAccount() error = %v", err)
+	}
+	if _, err := NewAccount("900", "12345671"); !errors.Is(err, errCheck) {
+		t.Errorf("NewAccount() error = %v, want %v", err, errCheck)
+	}
+
+	if err := Register(Bank{Code: "9", AccountLengths: []int{10}}); err == nil {
+		t.Error("Register() should return error for invalid code")
+	}
+	if err := Register(Bank{Code: "901", AccountLengths: []int{0}}); err == nil {
+		t.Error("Register() should return error for invalid account length")
+	}
+}
@@ -0,0 +1,8 @@
+// Package bank provides a registry of Thai banks and their account number rules.
+//
+// Banks are identified by their 3-digit code, as used in PromptPay bank
+// account proxies (Tag 29.04) and as the sending bank of Slip Verify QR codes.
+// For each bank the registry holds its Thai and English names, short name,
+// SWIFT BIC and whether it still operates, together with the account number
+// length, how to format it for display, and optional per-bank check rules.
+package bank
@@ -1,8 +1,8 @@
 package thaiqrgo
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -18,12 +18,40 @@ type BOTBarcode struct {
 	Ref2 *string
 
 	// Amount is the transaction amount (optional)
-	Amount *float64
+	Amount *Amount
 }
 
+// BarcodeVariant identifies a deviation from the standard BOT Barcode format,
+// as sent by some scanners and keyboard wedges.
+type BarcodeVariant string
+
+// BarcodeVariant values reported by lenient parsing.
+const (
+	BarcodeVariantLF                BarcodeVariant = "lf_separator"       // Fields separated by LF instead of CR
+	BarcodeVariantCRLF              BarcodeVariant = "crlf_separator"     // Fields separated by CR LF instead of CR
+	BarcodeVariantMissingPipe       BarcodeVariant = "missing_pipe"       // No leading '|'
+	BarcodeVariantTrailingSeparator BarcodeVariant = "trailing_separator" // CR or LF after the amount
+	BarcodeVariantFNC               BarcodeVariant = "fnc"                // Code 128 FNC characters in the data
+)
+
+// BOT Barcode field limits.
+const (
+	// BarcodeBillerIDLength is the length of a biller ID (13-digit tax ID and 2-digit suffix)
+	BarcodeBillerIDLength = 15
+
+	// MaxBarcodeRefLength is the maximum length of Ref1 and Ref2
+	MaxBarcodeRefLength = 18
+)
+
+// fncReplacer removes Code 128 FNC characters: GS, which scanners send for
+// FNC1, and the escape characters U+00F1-U+00F4 used by common barcode
+// libraries for FNC1-FNC4.
+var fncReplacer = strings.NewReplacer("\x1d", "", "\u00f1", "", "\u00f2", "", "\u00f3", "", "\u00f4", "")
+
 // BOTBarcodeFromString parses a BOT Barcode data string.
 //
 // The payload must start with '|' and contain 4 fields separated by '\r'.
+// Use ParseBarcodeWithOptions to accept scanner variants or enforce the BOT field rules.
 // Returns an error if the format is invalid.
 func BOTBarcodeFromString(payload string) (*BOTBarcode, error) {
 	if !strings.HasPrefix(payload, "|") {
@@ -45,15 +73,12 @@ func BOTBarcodeFromString(payload string) (*BOTBarcode, error) {
 		ref2Ptr = &ref2
 	}
 
-	var amountPtr *float64
+	var amountPtr *Amount
 	if amountStr != "0" {
-		amountInt, err := strconv.ParseInt(amountStr, 10, 64)
+		amount, err := ParseBarcodeAmount(amountStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid amount format: %w", err)
+			return nil, err
 		}
-		amount := float64(amountInt) / 100.0
-		// Round to 2 decimal places
-		amount = float64(int64(amount*100)) / 100.0
 		amountPtr = &amount
 	}
 
@@ -65,16 +90,73 @@ func BOTBarcodeFromString(payload string) (*BOTBarcode, error) {
 	}, nil
 }
 
+// BarcodeVariants reports the deviations from the standard BOT Barcode format
+// in a payload, as accepted by BarcodeParseOptions.Lenient.
+//
+// Returns the variants found, or nil for a payload in the standard format.
+func BarcodeVariants(payload string) []BarcodeVariant {
+	_, variants := lenientBarcode(payload)
+	return variants
+}
+
+// lenientBarcode rewrites a BOT Barcode sent in a variant format to the
+// standard format.
+//
+// Returns the rewritten payload and the variants found, in the order they
+// were handled.
+func lenientBarcode(payload string) (string, []BarcodeVariant) {
+	var variants []BarcodeVariant
+	if cleaned := fncReplacer.Replace(payload); cleaned != payload {
+		variants = append(variants, BarcodeVariantFNC)
+		payload = cleaned
+	}
+	// Trim first, so that a trailing CR LF or LF is not taken for the separator
+	if trimmed := strings.TrimRight(payload, "\r\n"); trimmed != payload {
+		variants = append(variants, BarcodeVariantTrailingSeparator)
+		payload = trimmed
+	}
+	if strings.Contains(payload, "\r\n") {
+		variants = append(variants, BarcodeVariantCRLF)
+		payload = strings.ReplaceAll(payload, "\r\n", "\r")
+	}
+	if strings.Contains(payload, "\n") {
+		variants = append(variants, BarcodeVariantLF)
+		payload = strings.ReplaceAll(payload, "\n", "\r")
+	}
+	if !strings.HasPrefix(payload, "|") {
+		variants = append(variants, BarcodeVariantMissingPipe)
+		payload = "|" + payload
+	}
+	return payload, variants
+}
+
+// checkBarcodeFields checks the fields of a parsed BOT Barcode against the
+// BOT format rules.
+//
+// Returns an error for the first broken rule.
+func checkBarcodeFields(b *BOTBarcode) error {
+	if len(b.BillerID) != BarcodeBillerIDLength || !isDigits(b.BillerID) {
+		return fmt.Errorf("invalid biller ID %q: must be %d digits", b.BillerID, BarcodeBillerIDLength)
+	}
+	if b.Ref1 == "" {
+		return errors.New("invalid Ref1: required")
+	}
+	if len(b.Ref1) > MaxBarcodeRefLength {
+		return fmt.Errorf("invalid Ref1 %q: max length %d", b.Ref1, MaxBarcodeRefLength)
+	}
+	if b.Ref2 != nil && len(*b.Ref2) > MaxBarcodeRefLength {
+		return fmt.Errorf("invalid Ref2 %q: max length %d", *b.Ref2, MaxBarcodeRefLength)
+	}
+	return nil
+}
+
 // String implements the fmt.Stringer interface.
 //
 // Returns the barcode in the standard BOT format: |billerID\rref1\rref2\ramount
 func (b *BOTBarcode) String() string {
-	var amountStr string
+	amountStr := "0"
 	if b.Amount != nil {
-		amountInt := int64(*b.Amount * 100)
-		amountStr = strconv.FormatInt(amountInt, 10)
-	} else {
-		amountStr = "0"
+		amountStr = b.Amount.BarcodeString()
 	}
 
 	ref2 := ""
@@ -1,6 +1,7 @@
 package thaiqrgo
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -39,7 +40,7 @@ func TestParseBarcode_WithRef2AndAmount(t *testing.T) {
 	if barcode.Ref2 == nil || *barcode.Ref2 != "670429" {
 		t.Errorf("ParseBarcode() Ref2 = %v, want 670429", barcode.Ref2)
 	}
-	if barcode.Amount == nil || *barcode.Amount != 3649.22 {
+	if barcode.Amount == nil || barcode.Amount.String() != "3649.22" {
 		t.Errorf("ParseBarcode() Amount = %v, want 3649.22", barcode.Amount)
 	}
 }
@@ -75,7 +76,7 @@ func TestBOTBarcode_String(t *testing.T) {
 
 	// Test with Ref2 and Amount
 	ref2 := "REF2"
-	amount := 100.50
+	amount := MustParseAmount("100.50")
 	barcode.Ref2 = &ref2
 	barcode.Amount = &amount
 	got = barcode.String()
@@ -85,7 +86,10 @@ func TestBOTBarcode_String(t *testing.T) {
 	}
 
 	// Test with amount that rounds
-	amount2 := 100.555
+	amount2, err := AmountFromFloat(100.555)
+	if err != nil {
+		t.Fatalf("AmountFromFloat() error = %v", err)
+	}
 	barcode.Amount = &amount2
 	got = barcode.String()
 	// Should round to 2 decimal places: 100.555 -> 10055 (100.55)
@@ -113,3 +117,73 @@ func TestParseBarcode_EdgeCases(t *testing.T) {
 		t.Error("ParseBarcode() with empty ref2 should set Ref2 to nil")
 	}
 }
+
+func TestParseBarcodeWithOptions_Lenient(t *testing.T) {
+	tests := []struct {
+		name     string
+		payload  string
+		variants []BarcodeVariant
+	}{
+		{"standard", "|099400016550100\r123456789012\r670429\r364922", nil},
+		{"LF", "|099400016550100\n123456789012\n670429\n364922", []BarcodeVariant{BarcodeVariantLF}},
+		{"CR LF", "|099400016550100\r\n123456789012\r\n670429\r\n364922", []BarcodeVariant{BarcodeVariantCRLF}},
+		{"missing pipe", "099400016550100\r123456789012\r670429\r364922", []BarcodeVariant{BarcodeVariantMissingPipe}},
+		{"trailing CR", "|099400016550100\r123456789012\r670429\r364922\r", []BarcodeVariant{BarcodeVariantTrailingSeparator}},
+		{"FNC1 as GS", "\x1d|099400016550100\r123456789012\r670429\r364922", []BarcodeVariant{BarcodeVariantFNC}},
+		{"FNC escapes", "ñ|099400016550100\r123456789012ó\r670429\r364922", []BarcodeVariant{BarcodeVariantFNC}},
+		{"trailing LF", "|099400016550100\r123456789012\r670429\r364922\n", []BarcodeVariant{BarcodeVariantTrailingSeparator}},
+		{"trailing CR LF", "|099400016550100\r123456789012\r670429\r364922\r\n", []BarcodeVariant{BarcodeVariantTrailingSeparator}},
+		{"keyboard wedge", "099400016550100\r\n123456789012\r\n670429\r\n364922\r\n", []BarcodeVariant{BarcodeVariantTrailingSeparator, BarcodeVariantCRLF, BarcodeVariantMissingPipe}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			barcode, err := ParseBarcodeWithOptions(tt.payload, BarcodeParseOptions{Lenient: true, Strict: true})
+			if err != nil {
+				t.Fatalf("ParseBarcodeWithOptions() error = %v", err)
+			}
+			if got := barcode.String(); got != "|099400016550100\r123456789012\r670429\r364922" {
+				t.Errorf("ParseBarcodeWithOptions() = %q", got)
+			}
+			if got := BarcodeVariants(tt.payload); !slices.Equal(got, tt.variants) {
+				t.Errorf("BarcodeVariants() = %v, want %v", got, tt.variants)
+			}
+		})
+	}
+
+	if _, err := ParseBarcodeWithOptions("099400016550100\n123456789012\n670429\n364922", BarcodeParseOptions{}); err == nil {
+		t.Error("ParseBarcodeWithOptions() should return error for a variant without Lenient")
+	}
+}
+
+func TestParseBarcodeWithOptions_Strict(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		wantErr bool
+	}{
+		{"valid", "|099400016550100\r123456789012\r670429\r364922", false},
+		{"no ref2 or amount", "|099999999999990\r111222333444\r\r0", false},
+		{"max length refs", "|099400016550100\r123456789012345678\r123456789012345678\r0", false},
+		{"short biller ID", "|09940001655010\r123456789012\r670429\r364922", true},
+		{"long biller ID", "|0994000165501000\r123456789012\r670429\r364922", true},
+		{"biller ID with letters", "|09940001655010A\r123456789012\r670429\r364922", true},
+		{"no ref1", "|099400016550100\r\r670429\r364922", true},
+		{"long ref1", "|099400016550100\r1234567890123456789\r670429\r364922", true},
+		{"long ref2", "|099400016550100\r123456789012\r1234567890123456789\r364922", true},
+		{"amount with decimals", "|099400016550100\r123456789012\r670429\r3649.22", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseBarcodeWithOptions(tt.payload, BarcodeParseOptions{Strict: true})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBarcodeWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := ParseBarcodeWithOptions("|09940001655010\r123456789012\r670429\r364922", BarcodeParseOptions{}); err != nil {
+		t.Errorf("ParseBarcodeWithOptions() without Strict error = %v", err)
+	}
+}
@@ -0,0 +1,253 @@
+package batch
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// Options configures a batch run.
+type Options struct {
+	// Format is the input format (default: FormatCSV)
+	Format Format
+
+	// Output is the output format (default: the input format)
+	Output Format
+
+	// Columns maps input column names to config field names, e.g.
+	// {"phone": "Target"}. Unmapped columns are used as field names.
+	Columns map[string]string
+
+	// Key is an input field copied to each result to identify the row,
+	// e.g. "branch_id" (optional)
+	Key string
+
+	// Workers is the number of rows generated concurrently (default: runtime.NumCPU())
+	Workers int
+}
+
+// Result is the outcome of a single row.
+type Result struct {
+	// Row is the 1-based row number
+	Row int `json:"row"`
+
+	// Key is the value of the Options.Key field, if set
+	Key string `json:"key,omitempty"`
+
+	// Payload is the generated payload, empty if generation failed
+	Payload string `json:"payload,omitempty"`
+
+	// Error describes why generation failed, empty on success
+	Error string `json:"error,omitempty"`
+}
+
+// Summary counts the rows of a batch run.
+type Summary struct {
+	// Rows is the number of rows written
+	Rows int
+
+	// Failed is the number of rows written with an error
+	Failed int
+}
+
+// Run reads rows from r, generates a payload for each with gen and writes the
+// results to w in input order.
+//
+// Rows are generated by a bounded pool of workers, and at most a few rows per
+// worker are held in memory at a time. A row that cannot be read or generated
+// is written with its error and does not stop the batch. Run stops with an
+// error if the input cannot be decoded any further, writing fails, or ctx is
+// cancelled; results written before that are complete and in order.
+func Run(ctx context.Context, r io.Reader, w io.Writer, gen Generator, opts Options) (Summary, error) {
+	if gen == nil {
+		return Summary{}, errors.New("batch: nil generator")
+	}
+	reader, err := newRowReader(r, opts.Format, opts.Columns)
+	if err != nil {
+		return Summary{}, err
+	}
+	output := opts.Output
+	if output == "" {
+		output = opts.Format
+	}
+	writer, err := newResultWriter(w, output)
+	if err != nil {
+		return Summary{}, err
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		index int
+		row   Row
+		err   error
+	}
+	type done struct {
+		index  int
+		result Result
+	}
+
+	// window bounds the rows between reading and writing, so that a slow row
+	// cannot make the reorder buffer grow without limit.
+	window := make(chan struct{}, workers*4)
+	jobs := make(chan job)
+	results := make(chan done, workers)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		for index := 0; ; index++ {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				readErr <- nil
+				return
+			}
+
+			row, err := reader.read()
+			if err == io.EOF {
+				readErr <- nil
+				return
+			}
+			var rowErr errRow
+			if err != nil && !errors.As(err, &rowErr) {
+				readErr <- fmt.Errorf("batch: row %d: %w", row.Number, err)
+				return
+			}
+
+			select {
+			case jobs <- job{index: index, row: row, err: err}:
+			case <-ctx.Done():
+				readErr <- nil
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result := Result{Row: j.row.Number}
+				if j.err == nil && opts.Key != "" {
+					result.Key = j.row.Get(opts.Key)
+				}
+				switch {
+				case j.err != nil:
+					result.Error = j.err.Error()
+				case ctx.Err() != nil:
+					return
+				default:
+					payload, err := gen(j.row)
+					if err != nil {
+						result.Error = err.Error()
+					} else {
+						result.Payload = payload
+					}
+				}
+
+				select {
+				case results <- done{index: j.index, result: result}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var summary Summary
+	pending := make(map[int]Result)
+	next := 0
+	for {
+		var d done
+		var ok bool
+		select {
+		case d, ok = <-results:
+		case <-ctx.Done():
+			ok = false
+		}
+		if !ok {
+			break
+		}
+
+		pending[d.index] = d.result
+		for {
+			result, ready := pending[next]
+			if !ready {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-window
+
+			if err := writer.write(result); err != nil {
+				return summary, fmt.Errorf("batch: failed to write result: %w", err)
+			}
+			summary.Rows++
+			if result.Error != "" {
+				summary.Failed++
+			}
+		}
+	}
+
+	if err := writer.flush(); err != nil {
+		return summary, fmt.Errorf("batch: failed to write result: %w", err)
+	}
+	if err := ctx.Err(); err != nil {
+		return summary, err
+	}
+	if err := <-readErr; err != nil {
+		return summary, err
+	}
+	return summary, nil
+}
+
+// resultWriter writes results as CSV or NDJSON.
+type resultWriter struct {
+	write func(Result) error
+	flush func() error
+}
+
+func newResultWriter(w io.Writer, format Format) (*resultWriter, error) {
+	switch format {
+	case FormatCSV, "":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"row", "key", "payload", "error"}); err != nil {
+			return nil, err
+		}
+		return &resultWriter{
+			write: func(r Result) error {
+				return writer.Write([]string{strconv.Itoa(r.Row), r.Key, r.Payload, r.Error})
+			},
+			flush: func() error {
+				writer.Flush()
+				return writer.Error()
+			},
+		}, nil
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		return &resultWriter{
+			write: func(r Result) error { return encoder.Encode(r) },
+			flush: func() error { return nil },
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported batch format %q", format)
+}
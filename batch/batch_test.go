@@ -0,0 +1,238 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_CSV(t *testing.T) {
+	input := "branch_id,type,phone,amount\n" +
+		"B001,MSISDN,0812345678,\n" +
+		"B002,MSISDN,021234567,100\n" +
+		"B003,NATID,1111111111119,12.50\n" +
+		"B004,MSISDN,0812345678,abc\n"
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), strings.NewReader(input), &out, AnyID, Options{
+		Columns: map[string]string{"phone": "Target"},
+		Key:     "branch_id",
+		Workers: 3,
+	})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary != (Summary{Rows: 4, Failed: 2}) {
+		t.Errorf("Run() summary = %+v, want 4 rows, 2 failed", summary)
+	}
+
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v", err)
+	}
+	if len(records) != 5 || strings.Join(records[0], ",") != "row,key,payload,error" {
+		t.Fatalf("output = %v, want header and 4 rows", records)
+	}
+
+	want := []struct {
+		key     string
+		payload string
+		failed  bool
+	}{
+		{"B001", "00020101021129370016A000000677010111011300668123456785303764580", false},
+		{"B002", "", true},
+		{"B003", "00020101021229370016A000000677010111021311111111111195303764", false},
+		{"B004", "", true},
+	}
+	for i, w := range want {
+		record := records[i+1]
+		if record[0] != strconv.Itoa(i+1) || record[1] != w.key {
+			t.Errorf("row %d = %v, want row %d key %s", i+1, record, i+1, w.key)
+		}
+		if !strings.HasPrefix(record[2], w.payload) {
+			t.Errorf("row %d payload = %v, want prefix %v", i+1, record[2], w.payload)
+		}
+		if (record[3] != "") != w.failed {
+			t.Errorf("row %d error = %q, want failed %v", i+1, record[3], w.failed)
+		}
+	}
+	if !strings.Contains(records[2][3], "Target") || !strings.Contains(records[4][3], "Amount") {
+		t.Errorf("row errors = %q, %q, want Target and Amount errors", records[2][3], records[4][3])
+	}
+}
+
+func TestRun_CSVByteOrderMark(t *testing.T) {
+	for _, header := range []string{"branch_id,type,phone\n", `"branch_id",type,phone` + "\n"} {
+		input := "\uFEFF" + header + "B001,MSISDN,0812345678\n"
+
+		var out bytes.Buffer
+		summary, err := Run(context.Background(), strings.NewReader(input), &out, AnyID, Options{
+			Columns: map[string]string{"phone": "Target"},
+			Key:     "branch_id",
+		})
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		if summary != (Summary{Rows: 1}) {
+			t.Errorf("Run() summary = %+v, want 1 row", summary)
+		}
+		records, err := csv.NewReader(&out).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not CSV: %v", err)
+		}
+		if len(records) != 2 || records[1][1] != "B001" {
+			t.Errorf("output = %v, want key B001", records)
+		}
+	}
+}
+
+func TestRun_NDJSON(t *testing.T) {
+	input := `{"biller_id": "099400016550100", "ref1": "123456789012", "amount": 364.92}
+{"biller_id": "099400016550100", "ref1": ["nested"]}
+[1, 2]
+{"BillerID": "", "Ref1": "1"}
+`
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), strings.NewReader(input), &out, BillPayment, Options{Format: FormatNDJSON})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary != (Summary{Rows: 4, Failed: 3}) {
+		t.Errorf("Run() summary = %+v, want 4 rows, 3 failed", summary)
+	}
+
+	var results []Result
+	decoder := json.NewDecoder(&out)
+	for decoder.More() {
+		var r Result
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("output is not NDJSON: %v", err)
+		}
+		results = append(results, r)
+	}
+	if len(results) != 4 {
+		t.Fatalf("output = %v, want 4 results", results)
+	}
+	if !strings.Contains(results[0].Payload, "5406364.92") || results[0].Error != "" {
+		t.Errorf("result 1 = %+v, want payload with amount 364.92", results[0])
+	}
+	for i, r := range results[1:] {
+		if r.Row != i+2 || r.Error == "" || r.Payload != "" {
+			t.Errorf("result %d = %+v, want error", i+2, r)
+		}
+	}
+}
+
+func TestRun_Ordered(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("n\n")
+	for i := 1; i <= 500; i++ {
+		fmt.Fprintf(&input, "%d\n", i)
+	}
+
+	slow := func(row Row) (string, error) {
+		time.Sleep(time.Duration(rand.IntN(200)) * time.Microsecond)
+		if n, _ := strconv.Atoi(row.Get("n")); n%7 == 0 {
+			return "", errors.New("multiple of 7")
+		}
+		return "P" + row.Get("n"), nil
+	}
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), strings.NewReader(input.String()), &out, slow, Options{Workers: 16, Output: FormatNDJSON})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary != (Summary{Rows: 500, Failed: 71}) {
+		t.Errorf("Run() summary = %+v, want 500 rows, 71 failed", summary)
+	}
+
+	decoder := json.NewDecoder(&out)
+	for i := 1; decoder.More(); i++ {
+		var r Result
+		if err := decoder.Decode(&r); err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		if r.Row != i || (i%7 != 0 && r.Payload != "P"+strconv.Itoa(i)) {
+			t.Fatalf("result %d = %+v, out of order", i, r)
+		}
+	}
+}
+
+func TestRun_Cancel(t *testing.T) {
+	var input strings.Builder
+	input.WriteString("n\n")
+	for i := 1; i <= 10000; i++ {
+		fmt.Fprintf(&input, "%d\n", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	gen := func(row Row) (string, error) {
+		if row.Number == 50 {
+			cancel()
+		}
+		return row.Get("n"), nil
+	}
+
+	var out bytes.Buffer
+	summary, err := Run(ctx, strings.NewReader(input.String()), &out, gen, Options{Workers: 4, Output: FormatNDJSON})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Run() error = %v, want context.Canceled", err)
+	}
+	if summary.Rows >= 10000 {
+		t.Errorf("Run() wrote %d rows after cancellation", summary.Rows)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != summary.Rows {
+		t.Errorf("output has %d lines, want %d", lines, summary.Rows)
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	if _, err := Run(context.Background(), strings.NewReader(""), &bytes.Buffer{}, AnyID, Options{}); err == nil {
+		t.Error("Run() without CSV header should return error")
+	}
+	if _, err := Run(context.Background(), strings.NewReader("a\n"), &bytes.Buffer{}, AnyID, Options{Format: "xml"}); err == nil {
+		t.Error("Run() with unknown format should return error")
+	}
+	if _, err := Run(context.Background(), strings.NewReader("a\n"), &bytes.Buffer{}, nil, Options{}); err == nil {
+		t.Error("Run() with nil generator should return error")
+	}
+
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), strings.NewReader("{\"type\": \"MSISDN\"}\n{oops\n"), &out, AnyID, Options{Format: FormatNDJSON})
+	if err == nil || summary.Rows != 1 {
+		t.Errorf("Run() with broken NDJSON = %+v, %v, want 1 row and error", summary, err)
+	}
+}
+
+func TestRun_MalformedCSVRow(t *testing.T) {
+	input := "type,target\nMSISDN,0812345678\nMSISDN\nMSISDN,0812345678\n"
+	var out bytes.Buffer
+	summary, err := Run(context.Background(), strings.NewReader(input), &out, AnyID, Options{})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if summary != (Summary{Rows: 3, Failed: 1}) {
+		t.Errorf("Run() summary = %+v, want 3 rows, 1 failed", summary)
+	}
+}
+
+func TestRow_Get(t *testing.T) {
+	row := Row{Fields: map[string]string{"billerid": "1"}}
+	for _, name := range []string{"BillerID", "biller_id", "Biller-ID", "biller id"} {
+		if got := row.Get(name); got != "1" {
+			t.Errorf("Get(%q) = %q, want 1", name, got)
+		}
+	}
+	if _, ok := row.Lookup("Ref1"); ok {
+		t.Error("Lookup(Ref1) ok = true, want false")
+	}
+}
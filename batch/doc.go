@@ -0,0 +1,13 @@
+// Package batch generates many QR codes at once from CSV or NDJSON input.
+//
+// Each input row is mapped to the fields of a generate config by column name
+// and turned into a payload by a Generator, e.g. AnyID or BillPayment. Run
+// generates rows with a bounded pool of workers and writes one result per row,
+// in input order, including rows that failed:
+//
+//	summary, err := batch.Run(ctx, input, output, batch.AnyID, batch.Options{
+//		Format:  batch.FormatCSV,
+//		Workers: 8,
+//		Key:     "branch_id",
+//	})
+package batch
@@ -0,0 +1,121 @@
+package batch
+
+import (
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/generate"
+)
+
+// Generator turns an input row into a payload.
+type Generator func(row Row) (string, error)
+
+// Built-in generators. Each reads the fields of its generate config by name
+// (see Row.Get); amounts are decimal strings such as "100.50" and optional
+// fields may be missing or empty.
+var (
+	// AnyID reads Type, Target, BankCode, WalletCode and Amount (see generate.AnyID)
+	AnyID Generator = func(row Row) (string, error) {
+		amount, err := rowAmount(row)
+		if err != nil {
+			return "", err
+		}
+		return generate.AnyID(generate.AnyIDConfig{
+			Type:       row.Get("Type"),
+			Target:     row.Get("Target"),
+			BankCode:   row.Get("BankCode"),
+			WalletCode: row.Get("WalletCode"),
+			Amount:     amount,
+		})
+	}
+
+	// BillPayment reads BillerID, Ref1, Ref2, Ref3 and Amount (see generate.BillPayment)
+	BillPayment Generator = func(row Row) (string, error) {
+		amount, err := rowAmount(row)
+		if err != nil {
+			return "", err
+		}
+		return generate.BillPayment(generate.BillPaymentConfig{
+			BillerID: row.Get("BillerID"),
+			Ref1:     row.Get("Ref1"),
+			Ref2:     optional(row, "Ref2"),
+			Ref3:     optional(row, "Ref3"),
+			Amount:   amount,
+		})
+	}
+
+	// BOTBarcode reads BillerID, Ref1, Ref2 and Amount (see generate.BOTBarcode)
+	BOTBarcode Generator = func(row Row) (string, error) {
+		amount, err := rowAmount(row)
+		if err != nil {
+			return "", err
+		}
+		return generate.BOTBarcode(generate.BOTBarcodeConfig{
+			BillerID: row.Get("BillerID"),
+			Ref1:     row.Get("Ref1"),
+			Ref2:     optional(row, "Ref2"),
+			Amount:   amount,
+		})
+	}
+
+	// TrueMoney reads MobileNo, Amount and Message (see generate.TrueMoney)
+	TrueMoney Generator = func(row Row) (string, error) {
+		amount, err := rowAmount(row)
+		if err != nil {
+			return "", err
+		}
+		return generate.TrueMoney(generate.TrueMoneyConfig{
+			MobileNo: row.Get("MobileNo"),
+			Amount:   amount,
+			Message:  optional(row, "Message"),
+		})
+	}
+
+	// CrossBorder reads AcquirerID, MerchantID, TerminalID, MCC, Name, City,
+	// PostalCode and Amount (see generate.CrossBorder)
+	CrossBorder Generator = func(row Row) (string, error) {
+		amount, err := rowAmount(row)
+		if err != nil {
+			return "", err
+		}
+		return generate.CrossBorder(generate.CrossBorderConfig{
+			AcquirerID: row.Get("AcquirerID"),
+			MerchantID: row.Get("MerchantID"),
+			TerminalID: row.Get("TerminalID"),
+			MCC:        row.Get("MCC"),
+			Name:       row.Get("Name"),
+			City:       row.Get("City"),
+			PostalCode: row.Get("PostalCode"),
+			Amount:     amount,
+		})
+	}
+)
+
+// Generators maps generator names, e.g. for a command line flag, to generators.
+var Generators = map[string]Generator{
+	"anyid":       AnyID,
+	"billpayment": BillPayment,
+	"botbarcode":  BOTBarcode,
+	"truemoney":   TrueMoney,
+	"crossborder": CrossBorder,
+}
+
+// rowAmount parses the optional Amount field.
+func rowAmount(row Row) (*thaiqrgo.Amount, error) {
+	value := row.Get("Amount")
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := thaiqrgo.ParseAmount(value)
+	if err != nil {
+		return nil, &generate.InvalidConfigError{Field: "Amount", Value: value, Rule: "decimal amount"}
+	}
+	return &amount, nil
+}
+
+// optional returns a pointer to a field value, or nil if the field is missing or empty.
+func optional(row Row, name string) *string {
+	value := row.Get(name)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
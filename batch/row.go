@@ -0,0 +1,172 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format is an input or output format.
+type Format string
+
+// Supported formats.
+const (
+	// FormatCSV is comma-separated values with a header row
+	FormatCSV Format = "csv"
+
+	// FormatNDJSON is one JSON object per line
+	FormatNDJSON Format = "ndjson"
+)
+
+// Row is an input row.
+type Row struct {
+	// Number is the 1-based row number, not counting the CSV header
+	Number int
+
+	// Fields maps normalized field names (see Row.Get) to values
+	Fields map[string]string
+}
+
+// Get returns the value of a field, matching names case-insensitively and
+// ignoring underscores, dashes and spaces, so "biller_id" matches "BillerID".
+func (r Row) Get(name string) string {
+	return r.Fields[normalize(name)]
+}
+
+// Lookup is like Get but also reports whether the field is present.
+func (r Row) Lookup(name string) (string, bool) {
+	value, ok := r.Fields[normalize(name)]
+	return value, ok
+}
+
+func normalize(name string) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(name) {
+		if c != '_' && c != '-' && c != ' ' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// bom is the UTF-8 byte order mark.
+var bom = []byte("\uFEFF")
+
+// rowReader reads rows from CSV or NDJSON input.
+type rowReader struct {
+	next func() (map[string]string, error)
+	// columns maps input column names to field names
+	columns map[string]string
+	number  int
+}
+
+// errRow is a problem with a single row that does not stop the batch.
+type errRow struct {
+	err error
+}
+
+func (e errRow) Error() string { return e.err.Error() }
+
+func newRowReader(r io.Reader, format Format, columns map[string]string) (*rowReader, error) {
+	rr := &rowReader{columns: make(map[string]string, len(columns))}
+	for column, field := range columns {
+		rr.columns[normalize(column)] = normalize(field)
+	}
+
+	switch format {
+	case FormatCSV, "":
+		// Skip the byte order mark that Excel writes at the start of UTF-8 CSV files
+		buffered := bufio.NewReader(r)
+		if prefix, _ := buffered.Peek(len(bom)); bytes.Equal(prefix, bom) {
+			buffered.Discard(len(bom))
+		}
+		reader := csv.NewReader(buffered)
+		reader.TrimLeadingSpace = true
+		reader.FieldsPerRecord = -1
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV header: %w", err)
+		}
+		rr.next = func() (map[string]string, error) {
+			record, err := reader.Read()
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, errRow{err}
+			}
+			if err != nil {
+				return nil, err
+			}
+			if len(record) != len(header) {
+				return nil, errRow{fmt.Errorf("row has %d columns, header has %d", len(record), len(header))}
+			}
+			values := make(map[string]string, len(header))
+			for i, name := range header {
+				values[name] = record[i]
+			}
+			return values, nil
+		}
+	case FormatNDJSON:
+		decoder := json.NewDecoder(r)
+		decoder.UseNumber()
+		rr.next = func() (map[string]string, error) {
+			var object map[string]any
+			if err := decoder.Decode(&object); err != nil {
+				var syntaxErr *json.SyntaxError
+				if errors.As(err, &syntaxErr) {
+					return nil, fmt.Errorf("invalid NDJSON: %w", err)
+				}
+				if errors.Is(err, io.EOF) {
+					return nil, io.EOF
+				}
+				return nil, errRow{fmt.Errorf("row is not a JSON object: %w", err)}
+			}
+			values := make(map[string]string, len(object))
+			for name, value := range object {
+				switch v := value.(type) {
+				case nil:
+				case string:
+					values[name] = v
+				case json.Number:
+					values[name] = v.String()
+				case bool:
+					values[name] = fmt.Sprint(v)
+				default:
+					return nil, errRow{fmt.Errorf("field %q must be a string, number or boolean", name)}
+				}
+			}
+			return values, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported batch format %q", format)
+	}
+	return rr, nil
+}
+
+// read returns the next row, io.EOF at the end of the input, an errRow for
+// a malformed row that can be skipped, or another error that stops the batch.
+func (rr *rowReader) read() (Row, error) {
+	values, err := rr.next()
+	if err == io.EOF {
+		return Row{}, io.EOF
+	}
+	rr.number++
+	row := Row{Number: rr.number}
+	if err != nil {
+		return row, err
+	}
+
+	row.Fields = make(map[string]string, len(values))
+	for name, value := range values {
+		name = normalize(strings.TrimSpace(name))
+		if field, ok := rr.columns[name]; ok {
+			name = field
+		}
+		row.Fields[name] = strings.TrimSpace(value)
+	}
+	return row, nil
+}
@@ -0,0 +1,279 @@
+package biller
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/klimakov/thai-qr-go/internal"
+	"github.com/klimakov/thai-qr-go/thaiid"
+)
+
+// Charset is the set of characters allowed in a reference.
+type Charset string
+
+// Supported character sets.
+const (
+	// CharsetAny allows any character
+	CharsetAny Charset = ""
+
+	// CharsetNumeric allows digits only
+	CharsetNumeric Charset = "numeric"
+
+	// CharsetAlphanumeric allows digits and uppercase letters A-Z
+	CharsetAlphanumeric Charset = "alphanumeric"
+)
+
+// Check digit schemes usable in Rule.CheckDigit.
+const (
+	// CheckDigitMod10 is the Luhn algorithm
+	CheckDigitMod10 = internal.CheckDigitMod10
+
+	// CheckDigitMod11 is a modulus 11 check with weights 2-7 from the right
+	CheckDigitMod11 = internal.CheckDigitMod11
+)
+
+// Rule describes the format of a reference (Ref1 or Ref2).
+type Rule struct {
+	// Required rejects an empty or missing reference
+	Required bool `json:"required,omitempty"`
+
+	// MinLength is the minimum length in characters (0: no minimum)
+	MinLength int `json:"min_length,omitempty"`
+
+	// MaxLength is the maximum length in characters (0: no maximum)
+	MaxLength int `json:"max_length,omitempty"`
+
+	// Charset is the set of allowed characters (default: any)
+	Charset Charset `json:"charset,omitempty"`
+
+	// CheckDigit is the check-digit scheme of the last digit, if any
+	// (CheckDigitMod10, CheckDigitMod11 or a scheme added with reference.Register)
+	CheckDigit string `json:"check_digit,omitempty"`
+}
+
+// check returns the name of the first rule that value breaks, or "" if it is valid.
+func (r Rule) check(value string) string {
+	if value == "" {
+		if r.Required {
+			return "required"
+		}
+		return ""
+	}
+
+	length := utf8.RuneCountInString(value)
+	switch {
+	case r.MinLength > 0 && length < r.MinLength:
+		return fmt.Sprintf("min length %d", r.MinLength)
+	case r.MaxLength > 0 && length > r.MaxLength:
+		return fmt.Sprintf("max length %d", r.MaxLength)
+	}
+
+	for _, c := range value {
+		digit := c >= '0' && c <= '9'
+		if (r.Charset == CharsetNumeric && !digit) ||
+			(r.Charset == CharsetAlphanumeric && !digit && (c < 'A' || c > 'Z')) {
+			return string(r.Charset)
+		}
+	}
+
+	if r.CheckDigit != "" && !internal.VerifyCheckDigit(r.CheckDigit, value) {
+		return r.CheckDigit + " check digit"
+	}
+	return ""
+}
+
+// validate checks the rule definition itself.
+func (r Rule) validate() error {
+	switch r.Charset {
+	case CharsetAny, CharsetNumeric, CharsetAlphanumeric:
+	default:
+		return fmt.Errorf("unknown charset %q", r.Charset)
+	}
+	if r.CheckDigit != "" && !internal.HasCheckDigit(r.CheckDigit) {
+		return fmt.Errorf("unknown check digit scheme %q", r.CheckDigit)
+	}
+	if r.MinLength < 0 || r.MaxLength < 0 || (r.MaxLength > 0 && r.MinLength > r.MaxLength) {
+		return fmt.Errorf("invalid length range %d-%d", r.MinLength, r.MaxLength)
+	}
+	return nil
+}
+
+// Biller describes a biller and the rules of its bill payments.
+type Biller struct {
+	// TaxID is the 13-digit tax ID of the biller
+	TaxID string `json:"tax_id"`
+
+	// Suffix is the 2-digit suffix that tells the biller's services apart (optional)
+	Suffix string `json:"suffix,omitempty"`
+
+	// Name is the biller name
+	Name string `json:"name"`
+
+	// NameTH is the Thai biller name (optional)
+	NameTH string `json:"name_th,omitempty"`
+
+	// Ref1 is the rule for reference 1
+	Ref1 Rule `json:"ref1"`
+
+	// Ref2 is the rule for reference 2
+	Ref2 Rule `json:"ref2"`
+
+	// AmountRequired rejects payments without an amount
+	AmountRequired bool `json:"amount_required,omitempty"`
+}
+
+// ID returns the biller ID: the tax ID followed by the suffix.
+func (b Biller) ID() string {
+	return b.TaxID + b.Suffix
+}
+
+// Validate checks bill payment data against the biller's rules.
+//
+// Returns a *RuleError for the first rule broken, or nil.
+func (b Biller) Validate(ref1 string, ref2 *string, hasAmount bool) error {
+	if rule := b.Ref1.check(ref1); rule != "" {
+		return &RuleError{BillerID: b.ID(), Field: "Ref1", Value: ref1, Rule: rule}
+	}
+
+	value := ""
+	if ref2 != nil {
+		value = *ref2
+	}
+	if rule := b.Ref2.check(value); rule != "" {
+		return &RuleError{BillerID: b.ID(), Field: "Ref2", Value: value, Rule: rule}
+	}
+
+	if b.AmountRequired && !hasAmount {
+		return &RuleError{BillerID: b.ID(), Field: "Amount", Value: "(none)", Rule: "required"}
+	}
+	return nil
+}
+
+// validate checks the biller definition itself.
+func (b Biller) validate() error {
+	if err := thaiid.Validate(b.TaxID); err != nil {
+		return fmt.Errorf("invalid biller %q: %w", b.ID(), err)
+	}
+	if b.Suffix != "" && (len(b.Suffix) != 2 || b.Suffix[0] < '0' || b.Suffix[0] > '9' || b.Suffix[1] < '0' || b.Suffix[1] > '9') {
+		return fmt.Errorf("invalid biller %q: suffix must be 2 digits", b.ID())
+	}
+	if err := b.Ref1.validate(); err != nil {
+		return fmt.Errorf("invalid biller %q: ref1: %w", b.ID(), err)
+	}
+	if err := b.Ref2.validate(); err != nil {
+		return fmt.Errorf("invalid biller %q: ref2: %w", b.ID(), err)
+	}
+	return nil
+}
+
+// RuleError reports bill payment data that breaks a biller rule.
+type RuleError struct {
+	// BillerID is the biller the rule belongs to
+	BillerID string
+
+	// Field is the field that broke the rule ("BillerID", "Ref1", "Ref2" or "Amount")
+	Field string
+
+	// Value is the rejected value
+	Value string
+
+	// Rule names the broken rule (e.g. "max length 10" or "mod10 check digit")
+	Rule string
+}
+
+func (e *RuleError) Error() string {
+	return "biller " + e.BillerID + ": " + e.Field + " = " + e.Value + " breaks rule: " + e.Rule
+}
+
+// Unwrap returns ErrUnknownBiller for billers missing from a strict directory.
+func (e *RuleError) Unwrap() error {
+	if e.Field == "BillerID" {
+		return ErrUnknownBiller
+	}
+	return nil
+}
+
+// ErrUnknownBiller is returned by a strict Directory for biller IDs it does not contain.
+var ErrUnknownBiller = errors.New("unknown biller")
+
+// Directory is a set of billers, looked up by biller ID.
+//
+// The zero value is an empty directory ready to use. A Directory is safe for
+// concurrent use.
+type Directory struct {
+	// Strict rejects biller IDs that are not in the directory.
+	// Otherwise unknown billers are accepted without checks.
+	Strict bool
+
+	mu      sync.RWMutex
+	billers map[string]Biller
+}
+
+// Default is the directory used when no other directory is given.
+var Default = &Directory{}
+
+// NewDirectory returns a directory holding the given billers.
+func NewDirectory(billers ...Biller) (*Directory, error) {
+	d := &Directory{}
+	for _, b := range billers {
+		if err := d.Add(b); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// Add adds a biller, or replaces the biller with the same ID.
+//
+// Returns an error if the tax ID, suffix or rules are invalid.
+func (d *Directory) Add(b Biller) error {
+	if err := b.validate(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.billers == nil {
+		d.billers = make(map[string]Biller)
+	}
+	d.billers[b.ID()] = b
+	return nil
+}
+
+// Lookup returns the biller with the given biller ID.
+func (d *Directory) Lookup(billerID string) (Biller, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	b, ok := d.billers[billerID]
+	return b, ok
+}
+
+// Billers returns every biller in the directory, sorted by biller ID.
+func (d *Directory) Billers() []Biller {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	billers := make([]Biller, 0, len(d.billers))
+	for _, b := range d.billers {
+		billers = append(billers, b)
+	}
+	sort.Slice(billers, func(i, j int) bool { return billers[i].ID() < billers[j].ID() })
+	return billers
+}
+
+// Validate checks bill payment data against the rules of the biller.
+//
+// Unknown billers are accepted, unless the directory is strict.
+// Returns a *RuleError describing the first rule broken, or nil.
+func (d *Directory) Validate(billerID, ref1 string, ref2 *string, hasAmount bool) error {
+	b, ok := d.Lookup(billerID)
+	if !ok {
+		if d.Strict {
+			return &RuleError{BillerID: billerID, Field: "BillerID", Value: billerID, Rule: ErrUnknownBiller.Error()}
+		}
+		return nil
+	}
+	return b.Validate(ref1, ref2, hasAmount)
+}
@@ -0,0 +1,107 @@
+package biller
+
+import (
+	"errors"
+	"testing"
+)
+
+func testBiller() Biller {
+	return Biller{
+		TaxID:          "0994000165501",
+		Suffix:         "00",
+		Name:           "Test Utility",
+		Ref1:           Rule{Required: true, MinLength: 8, MaxLength: 8, Charset: CharsetNumeric, CheckDigit: CheckDigitMod10},
+		Ref2:           Rule{MaxLength: 6, Charset: CharsetAlphanumeric},
+		AmountRequired: true,
+	}
+}
+
+func TestBiller_Validate(t *testing.T) {
+	b := testBiller()
+	ref2 := "INV01"
+	long := "INV0001"
+	lower := "inv01"
+
+	tests := []struct {
+		name      string
+		ref1      string
+		ref2      *string
+		hasAmount bool
+		field     string
+		rule      string
+	}{
+		{name: "valid", ref1: "12345674", ref2: &ref2, hasAmount: true},
+		{name: "valid without ref2", ref1: "12345674", hasAmount: true},
+		{name: "missing ref1", ref1: "", hasAmount: true, field: "Ref1", rule: "required"},
+		{name: "short ref1", ref1: "1234567", hasAmount: true, field: "Ref1", rule: "min length 8"},
+		{name: "letters in ref1", ref1: "1234567A", hasAmount: true, field: "Ref1", rule: "numeric"},
+		{name: "bad check digit", ref1: "12345670", hasAmount: true, field: "Ref1", rule: "mod10 check digit"},
+		{name: "long ref2", ref1: "12345674", ref2: &long, hasAmount: true, field: "Ref2", rule: "max length 6"},
+		{name: "lowercase ref2", ref1: "12345674", ref2: &lower, hasAmount: true, field: "Ref2", rule: "alphanumeric"},
+		{name: "missing amount", ref1: "12345674", field: "Amount", rule: "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := b.Validate(tt.ref1, tt.ref2, tt.hasAmount)
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("Biller.Validate() error = %v", err)
+				}
+				return
+			}
+
+			var ruleErr *RuleError
+			if !errors.As(err, &ruleErr) {
+				t.Fatalf("Biller.Validate() error = %v, want *RuleError", err)
+			}
+			if ruleErr.Field != tt.field || ruleErr.Rule != tt.rule {
+				t.Errorf("Biller.Validate() = %v/%v, want %v/%v", ruleErr.Field, ruleErr.Rule, tt.field, tt.rule)
+			}
+		})
+	}
+}
+
+func TestDirectory(t *testing.T) {
+	d, err := NewDirectory(testBiller())
+	if err != nil {
+		t.Fatalf("NewDirectory() error = %v", err)
+	}
+
+	b, ok := d.Lookup("099400016550100")
+	if !ok || b.Name != "Test Utility" {
+		t.Errorf("Directory.Lookup() = %v, %v, want Test Utility", b.Name, ok)
+	}
+	if len(d.Billers()) != 1 {
+		t.Errorf("Directory.Billers() = %d billers, want 1", len(d.Billers()))
+	}
+
+	if err := d.Validate("099400016550100", "1", nil, true); err == nil {
+		t.Error("Directory.Validate() should return error for invalid ref1")
+	}
+	if err := d.Validate("099999999999190", "anything", nil, false); err != nil {
+		t.Errorf("Directory.Validate() for unknown biller error = %v, want nil", err)
+	}
+
+	d.Strict = true
+	if err := d.Validate("099999999999190", "anything", nil, false); !errors.Is(err, ErrUnknownBiller) {
+		t.Errorf("Directory.Validate() for unknown biller error = %v, want %v", err, ErrUnknownBiller)
+	}
+}
+
+func TestDirectory_AddInvalid(t *testing.T) {
+	tests := []Biller{
+		{TaxID: "0994000165502", Name: "bad checksum"},
+		{TaxID: "0994000165501", Suffix: "1", Name: "bad suffix"},
+		{TaxID: "0994000165501", Ref1: Rule{Charset: "hex"}},
+		{TaxID: "0994000165501", Ref1: Rule{CheckDigit: "mod97"}},
+		{TaxID: "0994000165501", Ref2: Rule{MinLength: 5, MaxLength: 2}},
+	}
+
+	var d Directory
+	for _, b := range tests {
+		if err := d.Add(b); err == nil {
+			t.Errorf("Directory.Add(%+v) should return error", b)
+		}
+	}
+}
@@ -0,0 +1,11 @@
+// Package biller provides a directory of bill payment billers and their reference rules.
+//
+// Each biller is identified by its 13-digit tax ID and 2-digit suffix, the
+// biller ID used in PromptPay Bill Payment QR codes (Tag 30.01) and BOT Barcodes.
+// The directory records the length, character set and check-digit scheme of
+// Ref1 and Ref2, and whether an amount is required. Directories can be built
+// in code or loaded from JSON and CSV files.
+//
+// Default is empty until billers are added to it; the generate and parsing
+// functions validate against it when no other directory is given.
+package biller
@@ -0,0 +1,170 @@
+package biller
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadJSON adds the billers of a JSON array to the directory.
+//
+// Each element has the fields of Biller, e.g.:
+//
+//	[{"tax_id": "0994000165501", "suffix": "00", "name": "Revenue Department",
+//	  "ref1": {"required": true, "max_length": 13, "charset": "numeric"},
+//	  "amount_required": true}]
+func (d *Directory) LoadJSON(r io.Reader) error {
+	var billers []Biller
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&billers); err != nil {
+		return fmt.Errorf("failed to decode biller JSON: %w", err)
+	}
+
+	for _, b := range billers {
+		if err := d.Add(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// csvColumns are the columns accepted by LoadCSV.
+var csvColumns = []string{
+	"tax_id", "suffix", "name", "name_th",
+	"ref1_required", "ref1_min_length", "ref1_max_length", "ref1_charset", "ref1_check_digit",
+	"ref2_required", "ref2_min_length", "ref2_max_length", "ref2_charset", "ref2_check_digit",
+	"amount_required",
+}
+
+// LoadCSV adds the billers of a CSV file to the directory.
+//
+// The first row is a header naming the columns, in any order. Only tax_id is
+// mandatory; the other columns are suffix, name, name_th, amount_required and,
+// for each of ref1 and ref2, ref1_required, ref1_min_length, ref1_max_length,
+// ref1_charset and ref1_check_digit. Empty cells take the zero value.
+func (d *Directory) LoadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("failed to read biller CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !isCSVColumn(name) {
+			return fmt.Errorf("invalid biller CSV: unknown column %q", name)
+		}
+		columns[name] = i
+	}
+	if _, ok := columns["tax_id"]; !ok {
+		return errors.New("invalid biller CSV: missing tax_id column")
+	}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read biller CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		b, err := billerFromRecord(record, columns)
+		if err != nil {
+			return fmt.Errorf("invalid biller CSV line %d: %w", line, err)
+		}
+		if err := d.Add(b); err != nil {
+			return fmt.Errorf("invalid biller CSV line %d: %w", line, err)
+		}
+	}
+}
+
+// LoadFile adds the billers of a .json or .csv file to the directory.
+func (d *Directory) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return d.LoadJSON(f)
+	case ".csv":
+		return d.LoadCSV(f)
+	}
+	return fmt.Errorf("unsupported biller file type: %q (supported: .json, .csv)", filepath.Ext(path))
+}
+
+func isCSVColumn(name string) bool {
+	for _, column := range csvColumns {
+		if column == name {
+			return true
+		}
+	}
+	return false
+}
+
+// billerFromRecord builds a biller from a CSV record.
+func billerFromRecord(record []string, columns map[string]int) (Biller, error) {
+	get := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var errs []error
+	parseBool := func(name string) bool {
+		value := get(name)
+		if value == "" {
+			return false
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return b
+	}
+	parseInt := func(name string) int {
+		value := get(name)
+		if value == "" {
+			return 0
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+		return n
+	}
+	rule := func(prefix string) Rule {
+		return Rule{
+			Required:   parseBool(prefix + "_required"),
+			MinLength:  parseInt(prefix + "_min_length"),
+			MaxLength:  parseInt(prefix + "_max_length"),
+			Charset:    Charset(get(prefix + "_charset")),
+			CheckDigit: get(prefix + "_check_digit"),
+		}
+	}
+
+	b := Biller{
+		TaxID:          get("tax_id"),
+		Suffix:         get("suffix"),
+		Name:           get("name"),
+		NameTH:         get("name_th"),
+		Ref1:           rule("ref1"),
+		Ref2:           rule("ref2"),
+		AmountRequired: parseBool("amount_required"),
+	}
+	return b, errors.Join(errs...)
+}
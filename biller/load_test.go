@@ -0,0 +1,87 @@
+package biller
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testJSON = `[
+	{"tax_id": "0994000165501", "suffix": "00", "name": "Test Utility",
+	 "ref1": {"required": true, "min_length": 8, "max_length": 8, "charset": "numeric", "check_digit": "mod10"},
+	 "ref2": {"max_length": 6, "charset": "alphanumeric"},
+	 "amount_required": true}
+]`
+
+const testCSV = `tax_id,suffix,name,ref1_required,ref1_min_length,ref1_max_length,ref1_charset,ref1_check_digit,ref2_max_length,ref2_charset,amount_required
+0994000165501,00,Test Utility,true,8,8,numeric,mod10,6,alphanumeric,true
+`
+
+func TestDirectory_LoadJSON(t *testing.T) {
+	var d Directory
+	if err := d.LoadJSON(strings.NewReader(testJSON)); err != nil {
+		t.Fatalf("Directory.LoadJSON() error = %v", err)
+	}
+	assertTestBiller(t, &d)
+
+	if err := d.LoadJSON(strings.NewReader(`[{"tax_id": "0994000165501", "colour": "red"}]`)); err == nil {
+		t.Error("Directory.LoadJSON() should return error for unknown fields")
+	}
+	if err := d.LoadJSON(strings.NewReader(`[{"tax_id": "123"}]`)); err == nil {
+		t.Error("Directory.LoadJSON() should return error for invalid tax ID")
+	}
+}
+
+func TestDirectory_LoadCSV(t *testing.T) {
+	var d Directory
+	if err := d.LoadCSV(strings.NewReader(testCSV)); err != nil {
+		t.Fatalf("Directory.LoadCSV() error = %v", err)
+	}
+	assertTestBiller(t, &d)
+
+	invalid := []string{
+		"name\nTest\n",
+		"tax_id,colour\n0994000165501,red\n",
+		"tax_id,ref1_max_length\n0994000165501,ten\n",
+		"tax_id,amount_required\n0994000165501,maybe\n",
+	}
+	for _, data := range invalid {
+		if err := d.LoadCSV(strings.NewReader(data)); err == nil {
+			t.Errorf("Directory.LoadCSV(%q) should return error", data)
+		}
+	}
+}
+
+func TestDirectory_LoadFile(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{"billers.json": testJSON, "billers.csv": testCSV} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		var d Directory
+		if err := d.LoadFile(path); err != nil {
+			t.Fatalf("Directory.LoadFile(%s) error = %v", name, err)
+		}
+		assertTestBiller(t, &d)
+	}
+
+	var d Directory
+	if err := d.LoadFile(filepath.Join(dir, "billers.xml")); err == nil {
+		t.Error("Directory.LoadFile() should return error for missing file")
+	}
+}
+
+func assertTestBiller(t *testing.T, d *Directory) {
+	t.Helper()
+	b, ok := d.Lookup("099400016550100")
+	if !ok {
+		t.Fatal("Directory.Lookup(099400016550100) not found")
+	}
+	want := testBiller()
+	if b.Name != want.Name || b.Ref1 != want.Ref1 || b.Ref2 != want.Ref2 || b.AmountRequired != want.AmountRequired {
+		t.Errorf("Directory.Lookup() = %+v, want %+v", b, want)
+	}
+}
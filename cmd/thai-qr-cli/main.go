@@ -2,12 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	thaiqrgo "github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/bank"
+	"github.com/klimakov/thai-qr-go/biller"
+	"github.com/klimakov/thai-qr-go/dictionary"
+	"github.com/klimakov/thai-qr-go/ewallet"
+	"github.com/klimakov/thai-qr-go/phone"
 	"github.com/klimakov/thai-qr-go/validate"
 )
 
@@ -18,6 +25,9 @@ func main() {
 		payloadFlag = flag.String("payload", "", "QR code payload string to parse")
 		formatFlag  = flag.String("format", "json", "Output format: json, text (default: json)")
 		strictFlag  = flag.Bool("strict", false, "Validate CRC checksum (default: false)")
+		langFlag    = flag.String("lang", "en", "Language of tag names: en, th (default: en)")
+		normFlag    = flag.Bool("normalize", false, "Clean up scanner input (AIM prefix, BOM, whitespace, URL wrapping) before parsing")
+		billersFlag = flag.String("billers", "", "Biller directory file (.json or .csv) to check bill payment references against")
 		showVersion = flag.Bool("version", false, "Show version and exit")
 		helpFlag    = flag.Bool("help", false, "Show help message")
 	)
@@ -31,6 +41,8 @@ func main() {
 		fmt.Fprintf(os.Stderr, "  %s \"00020101021129370016A000000677010111011300668012345675802TH530376463046197\"\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -format text -strict \"00020101021129370016...\"\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s -payload \"|099999999999990\\r111222333444\\r\\r0\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -normalize \"]Q100020101021129370016...\"\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s -billers billers.csv \"00020101021230...\"\n", os.Args[0])
 	}
 
 	flag.Parse()
@@ -45,6 +57,20 @@ func main() {
 		os.Exit(0)
 	}
 
+	if lang := dictionary.Language(*langFlag); lang != dictionary.English && lang != dictionary.Thai {
+		fmt.Fprintf(os.Stderr, "Error: unknown language: %s (supported: en, th)\n", *langFlag)
+		os.Exit(1)
+	}
+
+	var billers *biller.Directory
+	if *billersFlag != "" {
+		billers = &biller.Directory{}
+		if err := billers.LoadFile(*billersFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading billers: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Get payload from flag or positional argument
 	payload := strings.TrimSpace(*payloadFlag)
 	if payload == "" {
@@ -57,6 +83,10 @@ func main() {
 	// Convert literal escape sequences to actual characters
 	payload = convertEscapes(payload)
 
+	if *normFlag {
+		payload = normalizePayload(payload)
+	}
+
 	if payload == "" {
 		fmt.Fprintf(os.Stderr, "Error: QR code payload is required\n\n")
 		flag.Usage()
@@ -65,7 +95,7 @@ func main() {
 
 	// Try to parse as BOT Barcode first (if starts with |)
 	if strings.HasPrefix(payload, "|") {
-		if err := parseBarcode(payload, *formatFlag); err != nil {
+		if err := parseBarcode(payload, *formatFlag, billers); err != nil {
 			fmt.Fprintf(os.Stderr, "Error parsing BOT Barcode: %v\n", err)
 			os.Exit(1)
 		}
@@ -73,20 +103,24 @@ func main() {
 	}
 
 	// Try to parse as EMVCo QR code
-	if err := parseQR(payload, *formatFlag, *strictFlag); err != nil {
+	if err := parseQR(payload, *formatFlag, *strictFlag, dictionary.Language(*langFlag), billers); err != nil {
 		fmt.Fprintf(os.Stderr, "Error parsing QR code: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func parseQR(payload, format string, strict bool) error {
-	qr, err := thaiqrgo.Parse(payload, strict, true)
+func parseQR(payload, format string, strict bool, lang dictionary.Language, billers *biller.Directory) error {
+	qr, err := thaiqrgo.ParseWithOptions(payload, thaiqrgo.ParseOptions{VerifyCRC: strict, SubTagDepth: 1, Billers: billers})
 	if err != nil {
 		return fmt.Errorf("failed to parse QR code: %w", err)
 	}
 
+	for _, warning := range qr.Diagnostics().Warnings() {
+		fmt.Fprintf(os.Stderr, "Warning: tag %s: %s\n", warning.Path, warning.Message)
+	}
+
 	// Try to identify QR code type and extract structured data
-	result := parseQRStructured(qr)
+	result := parseQRStructured(qr, lang)
 
 	switch strings.ToLower(format) {
 	case "text":
@@ -108,8 +142,17 @@ func convertEscapes(s string) string {
 	return s
 }
 
-func parseBarcode(payload, format string) error {
-	barcode, err := thaiqrgo.ParseBarcode(payload)
+// normalizePayload cleans up scanner input and reports what was removed on stderr
+func normalizePayload(payload string) string {
+	result := thaiqrgo.Normalize(payload)
+	for _, removal := range result.Removed {
+		fmt.Fprintf(os.Stderr, "Normalized: removed %s %q\n", removal.Kind, removal.Text)
+	}
+	return result.Payload
+}
+
+func parseBarcode(payload, format string, billers *biller.Directory) error {
+	barcode, err := thaiqrgo.ParseBarcodeWithOptions(payload, thaiqrgo.BarcodeParseOptions{Billers: billers})
 	if err != nil {
 		return fmt.Errorf("failed to parse BOT Barcode: %w", err)
 	}
@@ -137,132 +180,53 @@ type TagInfo struct {
 
 // QRCodeInfo contains extracted information from QR code
 type QRCodeInfo struct {
-	Type        string                            `json:"type,omitempty"`
-	PhoneNumber string                            `json:"phone_number,omitempty"`
-	NationalID  string                            `json:"national_id,omitempty"`
-	TaxID       string                            `json:"tax_id,omitempty"`
-	EWalletID   string                            `json:"ewallet_id,omitempty"`
-	Amount      *float64                          `json:"amount,omitempty"`
-	Currency    string                            `json:"currency,omitempty"`
-	Country     string                            `json:"country,omitempty"`
-	BillerID    string                            `json:"biller_id,omitempty"`
-	Ref1        string                            `json:"ref1,omitempty"`
-	Ref2        string                            `json:"ref2,omitempty"`
-	Ref3        string                            `json:"ref3,omitempty"`
-	Message     string                            `json:"message,omitempty"`
-	SlipVerify  *validate.SlipVerifyData          `json:"slip_verify,omitempty"`
-	TrueMoney   *validate.TrueMoneySlipVerifyData `json:"truemoney_slip_verify,omitempty"`
-	Tags        []TagInfo                         `json:"tags,omitempty"`
-	Valid       *bool                             `json:"valid,omitempty"`
-	CRCValid    bool                              `json:"crc_valid"`
-}
-
-// getTagName returns a human-readable name for a tag or sub-tag
-func getTagName(tagID, subTagID, parentTagID string) string {
-	// Main tags
-	tagNames := map[string]string{
-		"00": "Payload Format Indicator",
-		"01": "Point of Initiation",
-		"29": "Merchant Information",
-		"30": "Additional Data (Bill Payment)",
-		"51": "Country Code (Slip Verify)",
-		"52": "Merchant Category Code",
-		"53": "Transaction Currency",
-		"54": "Transaction Amount",
-		"58": "Country Code",
-		"59": "Merchant Name",
-		"60": "Merchant City",
-		"61": "Postal Code",
-		"62": "Additional Data Field Template",
-		"63": "CRC",
-		"81": "Personal Message",
-		"91": "CRC (Slip Verify)",
-	}
-
-	// Sub-tags of Tag 29 (PromptPay AnyID)
-	tag29SubNames := map[string]string{
-		"00": "GUID (A000000677010111)",
-		"01": "MSISDN (Mobile Number)",
-		"02": "NATID (National/Tax ID)",
-		"03": "EWALLETID (E-Wallet ID)",
-		"04": "BANKACC (Bank Account - Reserved)",
-	}
-
-	// Sub-tags of Tag 30 (Bill Payment)
-	tag30SubNames := map[string]string{
-		"00": "GUID (A000000677010112)",
-		"01": "Biller ID",
-		"02": "Reference 1",
-		"03": "Reference 2",
-	}
-
-	// Sub-tags of Tag 00 (Slip Verify / TrueMoney Slip Verify)
-	tag00SubNames := map[string]string{
-		"00": "API Type",
-		"01": "Sending Bank / API Type 01",
-		"02": "Transaction Reference / Event Type",
-		"03": "Transaction ID",
-		"04": "Date (DDMMYYYY)",
-	}
-
-	// Sub-tags of Tag 62 (Additional Data)
-	tag62SubNames := map[string]string{
-		"07": "Reference 3",
-	}
-
-	// If this is a sub-tag, check parent tag context
-	if subTagID != "" && parentTagID != "" {
-		switch parentTagID {
-		case "29":
-			if name, ok := tag29SubNames[subTagID]; ok {
-				return name
-			}
-		case "30":
-			if name, ok := tag30SubNames[subTagID]; ok {
-				return name
-			}
-		case "00":
-			if name, ok := tag00SubNames[subTagID]; ok {
-				return name
-			}
-		case "62":
-			if name, ok := tag62SubNames[subTagID]; ok {
-				return name
-			}
-		}
-		return fmt.Sprintf("Sub Tag %s", subTagID)
-	}
-
-	// Main tag
-	if name, ok := tagNames[tagID]; ok {
-		return name
-	}
-	return fmt.Sprintf("Tag %s", tagID)
+	Type            string                            `json:"type,omitempty"`
+	Schemes         []thaiqrgo.PaymentScheme          `json:"schemes,omitempty"`
+	PhoneNumber     string                            `json:"phone_number,omitempty"`
+	NationalID      string                            `json:"national_id,omitempty"`
+	TaxID           string                            `json:"tax_id,omitempty"`
+	EWalletID       string                            `json:"ewallet_id,omitempty"`
+	EWalletProvider string                            `json:"ewallet_provider,omitempty"`
+	BankCode        string                            `json:"bank_code,omitempty"`
+	BankName        string                            `json:"bank_name,omitempty"`
+	BankAccount     string                            `json:"bank_account,omitempty"`
+	Amount          *thaiqrgo.Amount                  `json:"amount,omitempty"`
+	Currency        string                            `json:"currency,omitempty"`
+	Country         string                            `json:"country,omitempty"`
+	BillerID        string                            `json:"biller_id,omitempty"`
+	Ref1            string                            `json:"ref1,omitempty"`
+	Ref2            string                            `json:"ref2,omitempty"`
+	Ref3            string                            `json:"ref3,omitempty"`
+	Message         string                            `json:"message,omitempty"`
+	Reference       string                            `json:"reference,omitempty"`
+	ExpiresAt       *time.Time                        `json:"expires_at,omitempty"`
+	Expired         *bool                             `json:"expired,omitempty"`
+	CrossBorder     *thaiqrgo.CrossBorderMerchant     `json:"cross_border,omitempty"`
+	SlipVerify      *validate.SlipVerifyData          `json:"slip_verify,omitempty"`
+	TrueMoney       *validate.TrueMoneySlipVerifyData `json:"truemoney_slip_verify,omitempty"`
+	Tags            []TagInfo                         `json:"tags,omitempty"`
+	Valid           *bool                             `json:"valid,omitempty"`
+	CRCValid        bool                              `json:"crc_valid"`
 }
 
 // convertTagsToInfo converts []TLVTag to []TagInfo with names
-func convertTagsToInfo(tags []thaiqrgo.TLVTag, parentTagID string) []TagInfo {
+func convertTagsToInfo(tags []thaiqrgo.TLVTag, parentPath string, lang dictionary.Language) []TagInfo {
 	result := make([]TagInfo, 0, len(tags))
 	for _, tag := range tags {
-		// Determine if this is a sub-tag
-		isSubTag := parentTagID != ""
-		var tagName string
-		if isSubTag {
-			tagName = getTagName(parentTagID, tag.ID, parentTagID)
-		} else {
-			tagName = getTagName(tag.ID, "", "")
-		}
-
 		tagInfo := TagInfo{
 			ID:     tag.ID,
-			Name:   tagName,
+			Name:   dictionary.Name(parentPath, tag.ID, lang),
 			Value:  tag.Value,
 			Length: tag.Length,
 		}
 
 		// Recursively process sub-tags
 		if len(tag.SubTags) > 0 {
-			tagInfo.SubTags = convertTagsToInfo(tag.SubTags, tag.ID)
+			path := tag.ID
+			if parentPath != "" {
+				path = parentPath + "." + tag.ID
+			}
+			tagInfo.SubTags = convertTagsToInfo(tag.SubTags, path, lang)
 		}
 
 		result = append(result, tagInfo)
@@ -270,7 +234,7 @@ func convertTagsToInfo(tags []thaiqrgo.TLVTag, parentTagID string) []TagInfo {
 	return result
 }
 
-func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
+func parseQRStructured(qr *thaiqrgo.EMVCoQR, lang dictionary.Language) QRCodeInfo {
 	info := QRCodeInfo{}
 
 	// Get basic tags
@@ -285,13 +249,19 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 		info.Country = country
 	}
 	if amountStr != "" {
-		// Try to parse amount
-		var amount float64
-		if _, err := fmt.Sscanf(amountStr, "%f", &amount); err == nil {
+		if amount, err := thaiqrgo.ParseAmount(amountStr); err == nil {
 			info.Amount = &amount
 		}
 	}
 
+	info.Schemes = qr.Schemes()
+	info.Reference = qr.ReferenceLabel()
+	if expiresAt, ok := qr.ExpiresAt(); ok {
+		expired := qr.Expired(time.Now())
+		info.ExpiresAt = &expiresAt
+		info.Expired = &expired
+	}
+
 	// Try to identify QR type and extract data
 	tag29 := qr.GetTag("29", "")
 	tag30 := qr.GetTag("30", "")
@@ -299,8 +269,21 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 
 	// Check for Slip Verify
 	slipVerify, err := validate.SlipVerify(qr.GetPayload())
+	if errors.Is(err, bank.ErrUnknownCode) {
+		// Still a Slip Verify QR, but from a bank missing in the registry
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		slipVerify = &validate.SlipVerifyData{
+			SendingBank: qr.GetTagValue("00", "01"),
+			TransRef:    qr.GetTagValue("00", "02"),
+		}
+		err = nil
+	}
 	if err == nil {
 		info.Type = "SlipVerify"
+		if b, ok := bank.Lookup(slipVerify.SendingBank); ok {
+			info.BankCode = b.Code
+			info.BankName = b.NameEN
+		}
 		info.SlipVerify = slipVerify
 		valid := qr.Validate("91")
 		info.CRCValid = valid
@@ -324,12 +307,10 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 		// Check sub-tags
 		phoneTag := qr.GetTag("29", "01")
 		if phoneTag != nil {
-			phone := phoneTag.Value
-			// Remove leading 66 and zeros, restore leading 0
-			if strings.HasPrefix(phone, "0066") && len(phone) == 13 {
-				phone = "0" + phone[4:]
+			info.PhoneNumber = phoneTag.Value
+			if number, err := phone.FromProxy(phoneTag.Value); err == nil {
+				info.PhoneNumber = number.Local()
 			}
-			info.PhoneNumber = phone
 		}
 
 		nidTag := qr.GetTag("29", "02")
@@ -338,20 +319,39 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 			info.TaxID = nidTag.Value
 		}
 
+		bankTag := qr.GetTag("29", "04")
+		if bankTag != nil {
+			info.BankAccount = bankTag.Value
+			if account, err := bank.ParseProxy(bankTag.Value); err == nil {
+				info.BankCode = account.Bank.Code
+				info.BankName = account.Bank.NameEN
+				info.BankAccount = account.Format()
+			}
+		}
+
 		ewalletTag := qr.GetTag("29", "03")
 		if ewalletTag != nil {
 			info.EWalletID = ewalletTag.Value
-		}
-
-		// Check for TrueMoney format (14000 prefix)
-		if phoneTag != nil && strings.HasPrefix(phoneTag.Value, "14000") {
-			info.Type = "TrueMoney"
-			info.PhoneNumber = strings.TrimPrefix(phoneTag.Value, "14000")
+			if wallet, err := ewallet.Parse(ewalletTag.Value); err == nil {
+				info.EWalletProvider = wallet.Provider.NameEN
+				if wallet.Provider.Code == ewallet.CodeTrueMoney {
+					info.Type = "TrueMoney"
+					info.PhoneNumber = wallet.Account
+				}
+			}
 		}
 	}
 
-	// PromptPay Bill Payment (Tag 30)
-	if tag30 != nil {
+	// PromptPay cross-border merchant (Tag 30 with the cross-border AID)
+	if merchant, ok := qr.CrossBorderMerchant(); ok {
+		info.Type = "PromptPayCrossBorder"
+		info.CrossBorder = merchant
+		if b, ok := bank.Lookup(merchant.AcquirerID); ok {
+			info.BankCode = b.Code
+			info.BankName = b.NameEN
+		}
+	} else if tag30 != nil {
+		// PromptPay Bill Payment (Tag 30)
 		info.Type = "PromptPayBillPayment"
 		billerID := qr.GetTagValue("30", "01")
 		ref1 := qr.GetTagValue("30", "02")
@@ -376,7 +376,11 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 
 	// Personal message (Tag 81) - decode from hex
 	if tag81 != "" {
-		info.Message = decodeTag81(tag81)
+		message, err := qr.PersonalMessage()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: tag 81: %v\n", err)
+		}
+		info.Message = message
 	}
 
 	// Validate CRC if Tag 63 or 91 exists
@@ -394,27 +398,11 @@ func parseQRStructured(qr *thaiqrgo.EMVCoQR) QRCodeInfo {
 	}
 
 	// Convert tags with names for JSON output
-	info.Tags = convertTagsToInfo(qr.GetTags(), "")
+	info.Tags = convertTagsToInfo(qr.GetTags(), "", lang)
 
 	return info
 }
 
-func decodeTag81(hexStr string) string {
-	var result strings.Builder
-	for i := 0; i < len(hexStr); i += 4 {
-		if i+4 > len(hexStr) {
-			break
-		}
-		var codePoint int
-		if _, err := fmt.Sscanf(hexStr[i:i+4], "%x", &codePoint); err != nil {
-			// If parsing fails, skip this character
-			continue
-		}
-		result.WriteRune(rune(codePoint))
-	}
-	return result.String()
-}
-
 func printQRJSON(qr *thaiqrgo.EMVCoQR, info QRCodeInfo) {
 	output := map[string]interface{}{
 		"payload": qr.GetPayload(),
@@ -437,6 +425,17 @@ func printQRText(qr *thaiqrgo.EMVCoQR, info QRCodeInfo) {
 	if info.Type != "" {
 		fmt.Printf("Type: %s\n", info.Type)
 	}
+	if len(info.Schemes) > 1 {
+		names := make([]string, 0, len(info.Schemes))
+		for _, s := range info.Schemes {
+			name := s.Name
+			if name == "" {
+				name = "unknown " + s.AID
+			}
+			names = append(names, fmt.Sprintf("%s (Tag %s)", name, s.TagID))
+		}
+		fmt.Printf("Schemes: %s\n", strings.Join(names, ", "))
+	}
 
 	if info.PhoneNumber != "" {
 		fmt.Printf("Phone Number: %s\n", info.PhoneNumber)
@@ -450,6 +449,15 @@ func printQRText(qr *thaiqrgo.EMVCoQR, info QRCodeInfo) {
 	if info.EWalletID != "" {
 		fmt.Printf("E-Wallet ID: %s\n", info.EWalletID)
 	}
+	if info.EWalletProvider != "" {
+		fmt.Printf("E-Wallet Provider: %s\n", info.EWalletProvider)
+	}
+	if info.BankName != "" && info.SlipVerify == nil {
+		fmt.Printf("Bank: %s (%s)\n", info.BankName, info.BankCode)
+	}
+	if info.BankAccount != "" {
+		fmt.Printf("Bank Account: %s\n", info.BankAccount)
+	}
 	if info.BillerID != "" {
 		fmt.Printf("Biller ID: %s\n", info.BillerID)
 	}
@@ -463,7 +471,7 @@ func printQRText(qr *thaiqrgo.EMVCoQR, info QRCodeInfo) {
 		fmt.Printf("Reference 3: %s\n", info.Ref3)
 	}
 	if info.Amount != nil {
-		fmt.Printf("Amount: %.2f\n", *info.Amount)
+		fmt.Printf("Amount: %s\n", info.Amount)
 	}
 	if info.Currency != "" {
 		fmt.Printf("Currency: %s\n", info.Currency)
@@ -474,10 +482,36 @@ func printQRText(qr *thaiqrgo.EMVCoQR, info QRCodeInfo) {
 	if info.Message != "" {
 		fmt.Printf("Message: %s\n", info.Message)
 	}
+	if info.Reference != "" {
+		fmt.Printf("Reference: %s\n", info.Reference)
+	}
+	if info.ExpiresAt != nil {
+		status := "valid"
+		if *info.Expired {
+			status = "expired"
+		}
+		fmt.Printf("Expires At: %s (%s)\n", info.ExpiresAt.Format(time.RFC3339), status)
+	}
+
+	if info.CrossBorder != nil {
+		fmt.Println("\nCross-Border Merchant:")
+		fmt.Printf("  Acquirer ID: %s\n", info.CrossBorder.AcquirerID)
+		fmt.Printf("  Merchant ID: %s\n", info.CrossBorder.MerchantID)
+		if info.CrossBorder.TerminalID != "" {
+			fmt.Printf("  Terminal ID: %s\n", info.CrossBorder.TerminalID)
+		}
+		fmt.Printf("  MCC: %s\n", info.CrossBorder.MCC)
+		fmt.Printf("  Name: %s\n", info.CrossBorder.Name)
+		fmt.Printf("  City: %s\n", info.CrossBorder.City)
+	}
 
 	if info.SlipVerify != nil {
 		fmt.Println("\nSlip Verify Data:")
-		fmt.Printf("  Sending Bank: %s\n", info.SlipVerify.SendingBank)
+		if info.BankName != "" {
+			fmt.Printf("  Sending Bank: %s (%s)\n", info.SlipVerify.SendingBank, info.BankName)
+		} else {
+			fmt.Printf("  Sending Bank: %s (unknown)\n", info.SlipVerify.SendingBank)
+		}
 		fmt.Printf("  Transaction Reference: %s\n", info.SlipVerify.TransRef)
 	}
 
@@ -538,6 +572,6 @@ func printBarcodeText(barcode *thaiqrgo.BOTBarcode) {
 	}
 
 	if barcode.Amount != nil {
-		fmt.Printf("Amount: %.2f\n", *barcode.Amount)
+		fmt.Printf("Amount: %s\n", barcode.Amount)
 	}
 }
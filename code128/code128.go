@@ -0,0 +1,257 @@
+package code128
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Symbol values with a special meaning.
+const (
+	startA = 103
+	startB = 104
+	startC = 105
+	stop   = 106
+
+	// shift encodes a single character of the other of code sets A and B
+	shift = 98
+
+	// codeC, codeB and codeA switch code sets
+	codeC = 99
+	codeB = 100
+	codeA = 101
+)
+
+// QuietZone is the minimum width of the blank margin on each side of a
+// barcode, in modules.
+const QuietZone = 10
+
+// ErrEmpty is returned when encoding an empty string.
+var ErrEmpty = errors.New("code128: empty data")
+
+// patterns are the bar and space widths of each symbol value, starting with a bar.
+// Every symbol is 11 modules wide except stop, which is 13.
+var patterns = [...]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+// codeSet is one of the Code 128 code sets.
+type codeSet int
+
+const (
+	setA codeSet = iota
+	setB
+	setC
+)
+
+// start returns the start symbol of the code set.
+func (s codeSet) start() int {
+	return startA + int(s)
+}
+
+// switchTo returns the symbol that switches to code set t. Each symbol has
+// the same value in the code sets it can be used in.
+func switchTo(t codeSet) int {
+	switch t {
+	case setA:
+		return codeA
+	case setB:
+		return codeB
+	}
+	return codeC
+}
+
+// value returns the symbol value of an ASCII character in code set A or B.
+func (s codeSet) value(c byte) (int, bool) {
+	switch {
+	case c >= 32 && c <= 95:
+		return int(c) - 32, true
+	case s == setA && c < 32:
+		return int(c) + 64, true
+	case s == setB && c >= 96 && c <= 127:
+		return int(c) - 32, true
+	}
+	return 0, false
+}
+
+// other returns code set B for A and A for B.
+func (s codeSet) other() codeSet {
+	return 1 - s
+}
+
+// Barcode is an encoded Code 128 barcode.
+type Barcode struct {
+	data    string
+	symbols []int
+}
+
+// Encode encodes ASCII data as a Code 128 barcode.
+//
+// Code sets are chosen to give the fewest symbols: digit runs use code set C,
+// control characters code set A and lowercase letters code set B, with single
+// characters of the other set shifted rather than switched to where shorter.
+// Returns an error if data is empty or contains non-ASCII characters.
+func Encode(data string) (*Barcode, error) {
+	if data == "" {
+		return nil, ErrEmpty
+	}
+	for i := 0; i < len(data); i++ {
+		if data[i] > 127 {
+			return nil, fmt.Errorf("code128: character %q at position %d is not ASCII", data[i], i)
+		}
+	}
+
+	symbols := plan(data)
+	checksum := symbols[0]
+	for i, symbol := range symbols[1:] {
+		checksum += (i + 1) * symbol
+	}
+	symbols = append(symbols, checksum%103, stop)
+	return &Barcode{data: data, symbols: symbols}, nil
+}
+
+// MustEncode is like Encode but panics on error.
+func MustEncode(data string) *Barcode {
+	b, err := Encode(data)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// step is one way to encode the data at a position without leaving a code set.
+type step struct {
+	cost  int // symbols
+	next  int // position after the step
+	shift bool
+}
+
+// steps returns the ways to encode data at position i in code set s.
+func steps(data string, i int, s codeSet) []step {
+	if s == setC {
+		if i+1 < len(data) && isDigit(data[i]) && isDigit(data[i+1]) {
+			return []step{{cost: 1, next: i + 2}}
+		}
+		return nil
+	}
+	if _, ok := s.value(data[i]); ok {
+		return []step{{cost: 1, next: i + 1}}
+	}
+	if _, ok := s.other().value(data[i]); ok {
+		return []step{{cost: 2, next: i + 1, shift: true}}
+	}
+	return nil
+}
+
+// plan returns the start symbol and data symbols of the shortest encoding,
+// found by dynamic programming over positions and code sets.
+func plan(data string) []int {
+	const unreachable = 1 << 30
+	sets := [...]codeSet{setC, setB, setA}
+
+	// direct[i][s] is the cost of the data from position i with the next step
+	// encoded in code set s; cost[i][s] also allows switching to another set first.
+	n := len(data)
+	direct := make([][3]int, n+1)
+	cost := make([][3]int, n+1)
+	best := make([][3]step, n)
+	for i := n - 1; i >= 0; i-- {
+		for _, s := range sets {
+			direct[i][s] = unreachable
+			for _, st := range steps(data, i, s) {
+				if total := st.cost + cost[st.next][s]; total < direct[i][s] {
+					direct[i][s] = total
+					best[i][s] = st
+				}
+			}
+		}
+		for _, s := range sets {
+			cost[i][s] = direct[i][s]
+			for _, t := range sets {
+				cost[i][s] = min(cost[i][s], 1+direct[i][t])
+			}
+		}
+	}
+
+	// The start symbol takes the place of the first switch.
+	current := setC
+	for _, s := range sets {
+		if direct[0][s] < direct[0][current] {
+			current = s
+		}
+	}
+	symbols := []int{current.start()}
+	for i := 0; i < n; {
+		if direct[i][current] > cost[i][current] {
+			for _, t := range sets {
+				if 1+direct[i][t] == cost[i][current] {
+					symbols = append(symbols, switchTo(t))
+					current = t
+					break
+				}
+			}
+		}
+		st := best[i][current]
+		switch {
+		case current == setC:
+			symbols = append(symbols, int(data[i]-'0')*10+int(data[i+1]-'0'))
+		case st.shift:
+			v, _ := current.other().value(data[i])
+			symbols = append(symbols, shift, v)
+		default:
+			v, _ := current.value(data[i])
+			symbols = append(symbols, v)
+		}
+		i = st.next
+	}
+	return symbols
+}
+
+// Data returns the encoded data.
+func (b *Barcode) Data() string {
+	return b.data
+}
+
+// Symbols returns the symbol values: start, data, check symbol and stop.
+func (b *Barcode) Symbols() []int {
+	return append([]int(nil), b.symbols...)
+}
+
+// Modules returns the barcode modules from the first bar to the last,
+// without quiet zones. true is a bar.
+func (b *Barcode) Modules() []bool {
+	var modules []bool
+	for _, symbol := range b.symbols {
+		for i, width := range patterns[symbol] {
+			for range width - '0' {
+				modules = append(modules, i%2 == 0)
+			}
+		}
+	}
+	return modules
+}
+
+// Text returns the human-readable text: the data with control characters
+// shown as spaces.
+func (b *Barcode) Text() string {
+	text := []byte(b.data)
+	for i, c := range text {
+		if c < 32 || c == 127 {
+			text[i] = ' '
+		}
+	}
+	return string(text)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
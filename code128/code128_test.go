@@ -0,0 +1,235 @@
+package code128
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// decodeModules decodes modules from the first bar to the last into data.
+func decodeModules(modules []bool) (string, error) {
+	runs := []float64{QuietZone}
+	for i := 0; i < len(modules); {
+		start := i
+		for i < len(modules) && modules[i] == modules[start] {
+			i++
+		}
+		runs = append(runs, float64(i-start))
+	}
+	runs = append(runs, QuietZone)
+	data, ok := decodeRuns(runs, false)
+	if !ok {
+		return "", errors.New("no barcode in modules")
+	}
+	return data, nil
+}
+
+// scan reads the modules along row y of a rendered barcode.
+func scan(t *testing.T, img image.Image, y, moduleWidth int) []bool {
+	t.Helper()
+	bounds := img.Bounds()
+	var pixels []bool
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		r, _, _, _ := img.At(x, y).RGBA()
+		pixels = append(pixels, r < 0x8000)
+	}
+	first, last := -1, -1
+	for x, dark := range pixels {
+		if dark {
+			if first < 0 {
+				first = x
+			}
+			last = x
+		}
+	}
+	if first < QuietZone*moduleWidth || len(pixels)-1-last < QuietZone*moduleWidth {
+		t.Fatalf("quiet zones are %d and %d pixels, want at least %d", first, len(pixels)-1-last, QuietZone*moduleWidth)
+	}
+	var modules []bool
+	for x := first; x <= last; x += moduleWidth {
+		modules = append(modules, pixels[x])
+	}
+	return modules
+}
+
+func TestPatterns(t *testing.T) {
+	seen := make(map[string]bool)
+	for v, pattern := range patterns {
+		modules, bars := 0, 0
+		for i, w := range pattern {
+			modules += int(w - '0')
+			if i%2 == 0 {
+				bars += int(w - '0')
+			}
+		}
+		want := 11
+		if v == stop {
+			want = 13
+		}
+		if modules != want || bars%2 != 0 || seen[pattern] {
+			t.Errorf("pattern %d = %s: %d modules, %d bar modules, duplicate %v", v, pattern, modules, bars, seen[pattern])
+		}
+		seen[pattern] = true
+	}
+	if len(patterns) != 107 {
+		t.Errorf("len(patterns) = %d, want 107", len(patterns))
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		data string
+		want []int
+	}{
+		{"Wikipedia", []int{startB, 55, 73, 75, 73, 80, 69, 68, 73, 65, 88, stop}},
+		{"123456", []int{startC, 12, 34, 56, 44, stop}},
+		{"\r", []int{startA, 77, 77, stop}},
+		{"a\rb", []int{startB, 65, shift, 77, 66, 36, stop}},
+		{"X1234567", []int{startB, 56, 17, codeC, 23, 45, 67, 77, stop}},
+		{"\r\r\rabc", []int{startA, 77, 77, 77, codeB, 65, 66, 67, 95, stop}},
+	}
+	for _, tt := range tests {
+		got, err := Encode(tt.data)
+		if err != nil {
+			t.Fatalf("Encode(%q) error = %v", tt.data, err)
+		}
+		if fmt.Sprint(got.Symbols()) != fmt.Sprint(tt.want) {
+			t.Errorf("Encode(%q).Symbols() = %v, want %v", tt.data, got.Symbols(), tt.want)
+		}
+	}
+}
+
+func TestEncode_Errors(t *testing.T) {
+	if _, err := Encode(""); !errors.Is(err, ErrEmpty) {
+		t.Errorf("Encode(\"\") error = %v, want %v", err, ErrEmpty)
+	}
+	if _, err := Encode("café"); err == nil {
+		t.Error("Encode() with non-ASCII data should return error")
+	}
+}
+
+func TestEncode_RoundTrip(t *testing.T) {
+	all := make([]byte, 128)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	random := rand.New(rand.NewPCG(1, 2))
+	inputs := []string{
+		"|099400016550100\r123456789012\r670429\r364922",
+		"|010555555555501\rINV-0042\r\r0",
+		"0",
+		string(all),
+	}
+	for range 50 {
+		data := make([]byte, 1+random.IntN(40))
+		for i := range data {
+			data[i] = "0123456789\r\naAzZ|-"[random.IntN(18)]
+		}
+		inputs = append(inputs, string(data))
+	}
+
+	for _, data := range inputs {
+		b := MustEncode(data)
+		got, err := decodeModules(b.Modules())
+		if err != nil || got != data {
+			t.Errorf("decode(Encode(%q)) = %q, %v", data, got, err)
+		}
+	}
+}
+
+func TestBarcode_Image(t *testing.T) {
+	data := "|099400016550100\r123456789012\r670429\r364922"
+	b := MustEncode(data)
+
+	for _, opts := range []Options{
+		{},
+		{ModuleWidth: 1, Height: 20},
+		{ModuleWidth: 3, QuietZone: 20, HumanReadable: true},
+	} {
+		img := b.Image(opts)
+		o := opts.withDefaults()
+		modules := scan(t, img, o.Height/2, o.ModuleWidth)
+		if got, err := decodeModules(modules); err != nil || got != data {
+			t.Errorf("decode(Image(%+v)) = %q, %v, want %q", opts, got, err, data)
+		}
+
+		wantHeight := o.Height
+		if opts.HumanReadable {
+			wantHeight += (glyphHeight + 4) * o.ModuleWidth
+		}
+		if img.Bounds().Dy() != wantHeight {
+			t.Errorf("Image(%+v) height = %d, want %d", opts, img.Bounds().Dy(), wantHeight)
+		}
+	}
+}
+
+func TestBarcode_HumanReadable(t *testing.T) {
+	b := MustEncode("|0994\r12")
+	if got := b.Text(); got != "|0994 12" {
+		t.Errorf("Text() = %q, want %q", got, "|0994 12")
+	}
+
+	img := b.Image(Options{HumanReadable: true})
+	dark := 0
+	for y := 61; y < img.Bounds().Dy(); y++ {
+		for x := range img.Bounds().Dx() {
+			if img.GrayAt(x, y).Y == 0 {
+				dark++
+			}
+		}
+	}
+	if dark == 0 {
+		t.Error("Image() with HumanReadable has no text under the bars")
+	}
+}
+
+func TestBarcode_WritePNG(t *testing.T) {
+	data := "|099400016550100\r123456789012\r\r0"
+	var buf bytes.Buffer
+	if err := MustEncode(data).WritePNG(&buf, Options{HumanReadable: true}); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if got, err := decodeModules(scan(t, img, 30, 2)); err != nil || got != data {
+		t.Errorf("decode(WritePNG()) = %q, %v, want %q", got, err, data)
+	}
+}
+
+func TestBarcode_WriteSVG(t *testing.T) {
+	data := "|099400016550100\r<ref>&\r\r0"
+	b := MustEncode(data)
+	var buf bytes.Buffer
+	if err := b.WriteSVG(&buf, Options{ModuleWidth: 1, HumanReadable: true}); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+	svg := buf.String()
+
+	var modules []bool
+	bars := regexp.MustCompile(`<rect x="(\d+)" y="0" width="(\d+)"`).FindAllStringSubmatch(svg, -1)
+	for _, bar := range bars {
+		x, _ := strconv.Atoi(bar[1])
+		width, _ := strconv.Atoi(bar[2])
+		for len(modules) < x-QuietZone {
+			modules = append(modules, false)
+		}
+		for range width {
+			modules = append(modules, true)
+		}
+	}
+	if got, err := decodeModules(modules); err != nil || got != data {
+		t.Errorf("decode(WriteSVG()) = %q, %v, want %q", got, err, data)
+	}
+	if !strings.Contains(svg, ">|099400016550100 &lt;ref&gt;&amp;  0</text>") {
+		t.Errorf("WriteSVG() text element missing or not escaped:\n%s", svg)
+	}
+}
@@ -0,0 +1,195 @@
+package code128
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrChecksum is returned for symbols whose check symbol does not match.
+var ErrChecksum = errors.New("code128: check symbol mismatch")
+
+// maxSymbolError is the largest total difference, in modules, between the
+// measured and the nominal element widths of a symbol.
+const maxSymbolError = 2.0
+
+// minQuietZone is the blank margin, in modules, required around a barcode
+// found in an image. It is below QuietZone to allow for tight crops.
+const minQuietZone = 3
+
+// decodeSymbols decodes symbol values, from start to stop, into data.
+//
+// FNC1-FNC4 are not supported: they have no representation in the data.
+func decodeSymbols(symbols []int) (string, error) {
+	n := len(symbols)
+	if n < 3 || symbols[0] < startA || symbols[0] > startC || symbols[n-1] != stop {
+		return "", errors.New("code128: missing start or stop symbol")
+	}
+	checksum := symbols[0]
+	for i, v := range symbols[1 : n-2] {
+		checksum += (i + 1) * v
+	}
+	if checksum%103 != symbols[n-2] {
+		return "", ErrChecksum
+	}
+
+	var data []byte
+	set := codeSet(symbols[0] - startA)
+	shifted := false
+	for _, v := range symbols[1 : n-2] {
+		current := set
+		if shifted {
+			current, shifted = set.other(), false
+		}
+		switch {
+		case current == setC && v < 100:
+			data = append(data, byte('0'+v/10), byte('0'+v%10))
+		case current != setC && v < 64:
+			data = append(data, byte(v+32))
+		case current == setA && v < 96:
+			data = append(data, byte(v-64))
+		case current == setB && v < 96:
+			data = append(data, byte(v+32))
+		case current != setC && v == shift:
+			shifted = true
+		case v == codeA:
+			set = setA
+		case v == codeB:
+			set = setB
+		case v == codeC:
+			set = setC
+		default:
+			return "", fmt.Errorf("code128: unsupported symbol %d", v)
+		}
+	}
+	return string(data), nil
+}
+
+// decodeRuns decodes a barcode from the widths of alternating dark and light
+// runs along a scanline. dark tells whether the first run is dark. Widths can
+// be fractional and in any unit; each symbol is scaled on its own, so the
+// module width may drift along the line.
+func decodeRuns(runs []float64, dark bool) (string, bool) {
+	for i := range runs {
+		if (i%2 == 0) != dark || i+6 > len(runs) {
+			continue
+		}
+		module := sum(runs[i:i+6]) / 11
+		if i > 0 && runs[i-1] < minQuietZone*module {
+			continue
+		}
+		if data, ok := decodeAt(runs, i); ok {
+			return data, true
+		}
+	}
+	return "", false
+}
+
+// decodeAt decodes a barcode whose start symbol begins at run i.
+//
+// Blur makes narrow elements look wider and wide elements narrower. The
+// distortion is measured on the start symbol, whose widths are known, and
+// undone for the rest of the barcode.
+func decodeAt(runs []float64, i int) (string, bool) {
+	start, fix := -1, widthFix{scale: 1}
+	for _, v := range []int{startA, startB, startC} {
+		f := fitWidths(runs[i:i+6], patterns[v])
+		if f.scale > 0.5 && patternError(runs[i:i+6], patterns[v], f) <= maxSymbolError {
+			start, fix = v, f
+			break
+		}
+	}
+	if start < 0 {
+		return "", false
+	}
+
+	symbols := []int{start}
+	width := sum(runs[i : i+6])
+	for i += 6; ; i += 6 {
+		if i+6 > len(runs) {
+			return "", false
+		}
+		symbol, symbolErr := matchSymbol(runs[i:i+6], fix)
+		if len(symbols) >= 2 && i+7 <= len(runs) {
+			stopWidth := sum(runs[i:i+7]) * 11 / 13
+			if stopErr := patternError(runs[i:i+7], patterns[stop], fix); stopErr < symbolErr && stopErr <= maxSymbolError && similarWidth(stopWidth, width) {
+				if i+7 < len(runs) && runs[i+7] < minQuietZone*stopWidth/11 {
+					return "", false
+				}
+				data, err := decodeSymbols(append(symbols, stop))
+				return data, err == nil
+			}
+		}
+		if symbolErr > maxSymbolError || !similarWidth(sum(runs[i:i+6]), width) {
+			return "", false
+		}
+		symbols = append(symbols, symbol)
+		width = sum(runs[i : i+6])
+	}
+}
+
+// similarWidth reports whether two neighbouring symbols have about the same
+// width, as they do unless a scanline leaves the barcode.
+func similarWidth(a, b float64) bool {
+	return a < 1.25*b && b < 1.25*a
+}
+
+// widthFix undoes a linear distortion of element widths in modules:
+// measured = scale*nominal + offset.
+type widthFix struct {
+	scale, offset float64
+}
+
+// fitWidths returns the distortion that maps the pattern to the runs scaled
+// to the pattern width, by least squares.
+func fitWidths(runs []float64, pattern string) widthFix {
+	modules := 0
+	for _, w := range pattern {
+		modules += int(w - '0')
+	}
+	mean := float64(modules) / float64(len(pattern))
+	unit := float64(modules) / sum(runs)
+	var cov, variance float64
+	for i, w := range pattern {
+		nominal := float64(w-'0') - mean
+		cov += nominal * (runs[i]*unit - mean)
+		variance += nominal * nominal
+	}
+	scale := cov / variance
+	return widthFix{scale: scale, offset: mean * (1 - scale)}
+}
+
+// matchSymbol returns the symbol value with the pattern closest to the runs,
+// and its error.
+func matchSymbol(runs []float64, fix widthFix) (int, float64) {
+	best, bestErr := -1, math.Inf(1)
+	for v, pattern := range patterns[:stop] {
+		if e := patternError(runs, pattern, fix); e < bestErr {
+			best, bestErr = v, e
+		}
+	}
+	return best, bestErr
+}
+
+// patternError returns the total difference, in modules, between the runs
+// scaled to the pattern width, corrected for distortion, and the pattern.
+func patternError(runs []float64, pattern string, fix widthFix) float64 {
+	modules := 0
+	for _, w := range pattern {
+		modules += int(w - '0')
+	}
+	unit := float64(modules) / sum(runs)
+	total := 0.0
+	for i, w := range pattern {
+		total += math.Abs((runs[i]*unit-fix.offset)/fix.scale - float64(w-'0'))
+	}
+	return total
+}
+
+func sum(values []float64) float64 {
+	total := 0.0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
@@ -0,0 +1,11 @@
+// Package code128 encodes and reads Code 128 barcodes, as printed on bills for BOT
+// Barcode payloads (see generate.BOTBarcode).
+//
+// Encode picks code sets A, B and C automatically to give the shortest
+// barcode, so control characters such as the carriage returns of a BOT
+// Barcode, lowercase letters and long digit runs can be mixed freely.
+// Barcodes render to an image.Image, PNG or SVG with quiet zones and,
+// optionally, human-readable text underneath.
+//
+// Scan reads a barcode back from a photo or scan of a bill, at any angle.
+package code128
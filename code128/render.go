@@ -0,0 +1,129 @@
+package code128
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// Options controls how a barcode is rendered.
+type Options struct {
+	// ModuleWidth is the width of the narrowest bar in pixels (default: 2)
+	ModuleWidth int
+
+	// Height is the bar height in pixels (default: 60)
+	Height int
+
+	// QuietZone is the blank margin on each side in modules
+	// (default and minimum: QuietZone)
+	QuietZone int
+
+	// HumanReadable shows the text returned by Barcode.Text under the bars
+	HumanReadable bool
+}
+
+// withDefaults returns the options with zero values replaced by defaults.
+func (o Options) withDefaults() Options {
+	if o.ModuleWidth <= 0 {
+		o.ModuleWidth = 2
+	}
+	if o.Height <= 0 {
+		o.Height = 60
+	}
+	o.QuietZone = max(o.QuietZone, QuietZone)
+	return o
+}
+
+// textScale returns the font scale for the human-readable text: the module
+// width, reduced if needed so that the text fits the image width.
+func (o Options) textScale(text string, width int) int {
+	scale := o.ModuleWidth
+	for scale > 1 && len(text)*glyphAdvance*scale > width {
+		scale--
+	}
+	return scale
+}
+
+// layout returns the image size and the height of the text area.
+func (b *Barcode) layout(o Options) (width, height, textHeight int) {
+	width = (len(b.Modules()) + 2*o.QuietZone) * o.ModuleWidth
+	height = o.Height
+	if o.HumanReadable {
+		scale := o.textScale(b.Text(), width)
+		textHeight = (glyphHeight + 4) * scale
+		height += textHeight
+	}
+	return width, height, textHeight
+}
+
+// Image renders the barcode as black bars on a white background.
+func (b *Barcode) Image(opts Options) *image.Gray {
+	o := opts.withDefaults()
+	width, height, _ := b.layout(o)
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	x := o.QuietZone * o.ModuleWidth
+	for _, bar := range b.Modules() {
+		if bar {
+			for dx := range o.ModuleWidth {
+				for y := range o.Height {
+					img.SetGray(x+dx, y, color.Gray{})
+				}
+			}
+		}
+		x += o.ModuleWidth
+	}
+
+	if o.HumanReadable {
+		text := b.Text()
+		scale := o.textScale(text, width)
+		left := (width - len(text)*glyphAdvance*scale) / 2
+		drawText(img, text, max(left, 0), o.Height+2*scale, scale)
+	}
+	return img
+}
+
+// WritePNG writes the barcode as a PNG image.
+func (b *Barcode) WritePNG(w io.Writer, opts Options) error {
+	return png.Encode(w, b.Image(opts))
+}
+
+// WriteSVG writes the barcode as an SVG image with one rectangle per bar.
+// The human-readable text is a monospace text element.
+func (b *Barcode) WriteSVG(w io.Writer, opts Options) error {
+	o := opts.withDefaults()
+	width, height, textHeight := b.layout(o)
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(out, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", width, height)
+
+	modules := b.Modules()
+	for i := 0; i < len(modules); {
+		if !modules[i] {
+			i++
+			continue
+		}
+		start := i
+		for i < len(modules) && modules[i] {
+			i++
+		}
+		fmt.Fprintf(out, `<rect x="%d" y="0" width="%d" height="%d" fill="#000"/>`+"\n",
+			(o.QuietZone+start)*o.ModuleWidth, (i-start)*o.ModuleWidth, o.Height)
+	}
+
+	if o.HumanReadable {
+		fmt.Fprintf(out, `<text x="%d" y="%d" font-family="monospace" font-size="%d" text-anchor="middle" xml:space="preserve">%s</text>`+"\n",
+			width/2, height-textHeight/4, textHeight*3/4, html.EscapeString(b.Text()))
+	}
+	fmt.Fprintln(out, `</svg>`)
+	return out.Flush()
+}
@@ -0,0 +1,270 @@
+package code128
+
+import (
+	"cmp"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// ErrNotFound is returned when no barcode can be decoded in an image.
+var ErrNotFound = errors.New("code128: no barcode found")
+
+// Scan parameters.
+const (
+	// scanAngleStep is the angle between scan directions in degrees when no
+	// dominant direction is found. A scanline must cross every bar, so the step
+	// must stay well below the angle given by the bar height over the barcode width.
+	scanAngleStep = 2
+
+	// scanMinSpacing is the smallest distance between parallel scanlines in pixels
+	scanMinSpacing = 4
+
+	// scanSampleStep is the distance between samples along a scanline in pixels
+	scanSampleStep = 0.5
+
+	// scanPeaks is the number of dominant edge directions scanned first
+	scanPeaks = 4
+)
+
+// Scan finds a Code 128 barcode in an image, such as a photo or scan of a bill,
+// and returns its data.
+//
+// The barcode may be rotated by any angle, slightly blurred and noisy. Scan
+// first reads scanlines across the dominant edge directions of the image,
+// where the bars of a barcode stand out, then falls back to a coarse sweep of
+// every direction. It returns the first scanline that decodes with a valid
+// check symbol. The data of a BOT Barcode can be passed to
+// thaiqrgo.BOTBarcodeFromString.
+//
+// Returns ErrNotFound if no barcode is found.
+func Scan(img image.Image) (string, error) {
+	gray := toGray(img)
+	bounds := gray.Bounds()
+	cx := float64(bounds.Min.X+bounds.Max.X) / 2
+	cy := float64(bounds.Min.Y+bounds.Max.Y) / 2
+	radius := math.Hypot(float64(bounds.Dx()), float64(bounds.Dy())) / 2
+
+	var peaks []float64
+	for _, peak := range orientations(gray) {
+		peaks = append(peaks, peak, peak-1, peak+1, peak-2, peak+2)
+	}
+	passes := []struct {
+		angles  []float64
+		spacing float64
+	}{
+		{peaks, scanMinSpacing},
+		{sweepAngles(), radius / 8},
+	}
+	for _, pass := range passes {
+		for _, offset := range scanOffsets(radius, pass.spacing) {
+			for _, angle := range pass.angles {
+				sin, cos := math.Sincos(angle * math.Pi / 180)
+				profile := sampleLine(gray, cx-offset*sin, cy+offset*cos, cos, sin, radius)
+				runs, dark := binarize(profile)
+				if data, ok := decodeRuns(runs, dark); ok {
+					return data, nil
+				}
+				slices.Reverse(runs)
+				if data, ok := decodeRuns(runs, dark == (len(runs)%2 == 1)); ok {
+					return data, nil
+				}
+			}
+		}
+	}
+	return "", ErrNotFound
+}
+
+// orientations returns the dominant edge directions of an image in degrees
+// (0-180), strongest first. Bars of a barcode give a sharp peak in the
+// histogram of gradient directions, at the direction a scanline must follow.
+func orientations(img *image.Gray) []float64 {
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/800)
+	var histogram [180]float64
+	for y := bounds.Min.Y + 1; y < bounds.Max.Y-1; y += step {
+		for x := bounds.Min.X + 1; x < bounds.Max.X-1; x += step {
+			gx := float64(img.Pix[img.PixOffset(x+1, y)]) - float64(img.Pix[img.PixOffset(x-1, y)])
+			gy := float64(img.Pix[img.PixOffset(x, y+1)]) - float64(img.Pix[img.PixOffset(x, y-1)])
+			magnitude := math.Hypot(gx, gy)
+			if magnitude < 32 {
+				continue
+			}
+			angle := math.Atan2(gy, gx) * 180 / math.Pi
+			histogram[(int(math.Round(angle))+360)%180] += magnitude
+		}
+	}
+
+	var smoothed [180]float64
+	for i := range smoothed {
+		for d := -2; d <= 2; d++ {
+			smoothed[i] += histogram[(i+d+180)%180]
+		}
+	}
+	var peaks []int
+	for i, v := range smoothed {
+		if v > 0 && v >= smoothed[(i+179)%180] && v > smoothed[(i+1)%180] {
+			peaks = append(peaks, i)
+		}
+	}
+	slices.SortFunc(peaks, func(a, b int) int {
+		return cmp.Compare(smoothed[b], smoothed[a])
+	})
+
+	angles := make([]float64, 0, scanPeaks)
+	for _, peak := range peaks[:min(len(peaks), scanPeaks)] {
+		angles = append(angles, float64(peak))
+	}
+	return angles
+}
+
+// sweepAngles returns every scan direction in degrees, horizontal and vertical
+// first. Directions 180 degrees apart give the same scanline reversed, which
+// Scan decodes both ways.
+func sweepAngles() []float64 {
+	angles := []float64{0, 90}
+	for delta := float64(scanAngleStep); delta < 90; delta += scanAngleStep {
+		angles = append(angles, delta, -delta, 90+delta, 90-delta)
+	}
+	return angles
+}
+
+// scanOffsets returns the distances of parallel scanlines from the centre,
+// coarse to fine down to minSpacing, so that a barcode near the centre is found first.
+func scanOffsets(radius, minSpacing float64) []float64 {
+	offsets := []float64{0}
+	for spacing := radius / 2; spacing >= minSpacing; spacing /= 2 {
+		for offset := spacing; offset < radius; offset += 2 * spacing {
+			offsets = append(offsets, offset, -offset)
+		}
+	}
+	return offsets
+}
+
+// sampleLine returns the luminance along the line through (x, y) in direction
+// (dx, dy), radius pixels to each side. Each sample averages three points
+// across the line to reduce noise; points outside the image are white.
+func sampleLine(img *image.Gray, x, y, dx, dy, radius float64) []float64 {
+	n := int(2 * radius / scanSampleStep)
+	profile := make([]float64, n)
+	for i := range profile {
+		s := -radius + float64(i)*scanSampleStep
+		px, py := x+s*dx, y+s*dy
+		profile[i] = (luminance(img, px, py) + luminance(img, px-dy, py+dx) + luminance(img, px+dy, py-dx)) / 3
+	}
+	return profile
+}
+
+// luminance returns the bilinearly interpolated luminance at a point.
+func luminance(img *image.Gray, x, y float64) float64 {
+	bounds := img.Bounds()
+	x, y = x-0.5, y-0.5
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	fx, fy := x-float64(x0), y-float64(y0)
+	at := func(x, y int) float64 {
+		if !(image.Point{X: x, Y: y}.In(bounds)) {
+			return 255
+		}
+		return float64(img.Pix[img.PixOffset(x, y)])
+	}
+	top := at(x0, y0)*(1-fx) + at(x0+1, y0)*fx
+	bottom := at(x0, y0+1)*(1-fx) + at(x0+1, y0+1)*fx
+	return top*(1-fy) + bottom*fy
+}
+
+// binarize splits a luminance profile into runs of dark and light samples and
+// returns their widths. dark tells whether the first run is dark.
+//
+// Bars and spaces are the valleys and peaks of the profile that stand out from
+// their neighbours by more than the noise. Each edge lies where the profile
+// crosses the midpoint between the peak and the valley on either side of it,
+// so that narrow bars and spaces dimmed by blur keep their width.
+func binarize(profile []float64) ([]float64, bool) {
+	if len(profile) == 0 {
+		return nil, false
+	}
+	sorted := slices.Clone(profile)
+	slices.Sort(sorted)
+	contrast := sorted[len(sorted)*95/100] - sorted[len(sorted)*5/100]
+	if contrast < 32 {
+		return []float64{float64(len(profile))}, false
+	}
+
+	extrema := findExtrema(profile, contrast/5)
+	if len(extrema) < 2 {
+		return []float64{float64(len(profile))}, false
+	}
+
+	var runs []float64
+	edge := 0.0
+	for k := 1; k < len(extrema); k++ {
+		from, to := extrema[k-1], extrema[k]
+		threshold := (profile[from] + profile[to]) / 2
+		for i := from + 1; i <= to; i++ {
+			if (profile[i] >= threshold) != (profile[from] >= threshold) {
+				crossing := float64(i-1) + (threshold-profile[i-1])/(profile[i]-profile[i-1])
+				runs = append(runs, crossing-edge)
+				edge = crossing
+				break
+			}
+		}
+	}
+	runs = append(runs, float64(len(profile))-edge)
+	return runs, profile[extrema[0]] < profile[extrema[1]]
+}
+
+// findExtrema returns the indexes of alternating local maxima and minima of
+// the profile that differ from the previous extremum by at least delta.
+func findExtrema(profile []float64, delta float64) []int {
+	var extrema []int
+	lo, hi := 0, 0
+	rising := 0 // 1 after a minimum, -1 after a maximum, 0 at the start
+	for i, v := range profile {
+		if v > profile[hi] {
+			hi = i
+		}
+		if v < profile[lo] {
+			lo = i
+		}
+		switch {
+		case rising >= 0 && v < profile[hi]-delta:
+			extrema = append(extrema, hi)
+			rising, lo = -1, i
+		case rising <= 0 && v > profile[lo]+delta:
+			extrema = append(extrema, lo)
+			rising, hi = 1, i
+		}
+	}
+	if rising > 0 {
+		extrema = append(extrema, hi)
+	} else if rising < 0 {
+		extrema = append(extrema, lo)
+	}
+	return extrema
+}
+
+// toGray returns the luminance of an image.
+func toGray(img image.Image) *image.Gray {
+	switch img := img.(type) {
+	case *image.Gray:
+		return img
+	case *image.YCbCr:
+		gray := image.NewGray(img.Bounds())
+		for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+			for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+				gray.Pix[gray.PixOffset(x, y)] = img.Y[img.YOffset(x, y)]
+			}
+		}
+		return gray
+	}
+	bounds := img.Bounds()
+	gray := image.NewGray(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			gray.Set(x, y, color.GrayModel.Convert(img.At(x, y)))
+		}
+	}
+	return gray
+}
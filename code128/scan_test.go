@@ -0,0 +1,151 @@
+package code128
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+// photo places a barcode image rotated by angle degrees on a larger white page,
+// then blurs it with a box filter of the given radius and adds noise.
+func photo(src *image.Gray, angle float64, blur int, noise float64) *image.Gray {
+	size := int(math.Hypot(float64(src.Bounds().Dx()), float64(src.Bounds().Dy()))) + 80
+	page := image.NewGray(image.Rect(0, 0, size, size))
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+	sx, sy := float64(src.Bounds().Dx())/2, float64(src.Bounds().Dy())/2
+	for y := range size {
+		for x := range size {
+			// Inverse rotation around the page centre
+			dx, dy := float64(x)+0.5-float64(size)/2, float64(y)+0.5-float64(size)/2
+			page.Pix[page.PixOffset(x, y)] = uint8(luminance(src, sx+dx*cos+dy*sin, sy-dx*sin+dy*cos))
+		}
+	}
+
+	if blur > 0 {
+		blurred := image.NewGray(page.Bounds())
+		for y := range size {
+			for x := range size {
+				total, count := 0, 0
+				for ky := max(0, y-blur); ky <= min(size-1, y+blur); ky++ {
+					for kx := max(0, x-blur); kx <= min(size-1, x+blur); kx++ {
+						total += int(page.Pix[page.PixOffset(kx, ky)])
+						count++
+					}
+				}
+				blurred.Pix[blurred.PixOffset(x, y)] = uint8(total / count)
+			}
+		}
+		page = blurred
+	}
+
+	random := rand.New(rand.NewPCG(uint64(angle*100), 7))
+	for i, v := range page.Pix {
+		page.Pix[i] = uint8(min(max(float64(v)+random.NormFloat64()*noise, 0), 255))
+	}
+	return page
+}
+
+func TestScan(t *testing.T) {
+	data := "|099400016550100\r123456789012\r670429\r364922"
+	barcode := MustEncode(data)
+
+	tests := []struct {
+		name  string
+		opts  Options
+		angle float64
+		blur  int
+		noise float64
+	}{
+		{"straight", Options{}, 0, 0, 0},
+		{"human readable", Options{HumanReadable: true}, 0, 0, 0},
+		{"upside down", Options{}, 180, 0, 0},
+		{"vertical", Options{}, 90, 0, 0},
+		{"rotated", Options{ModuleWidth: 3}, 37, 0, 0},
+		{"rotated back", Options{ModuleWidth: 3}, -113, 0, 0},
+		{"blurred", Options{ModuleWidth: 3}, 0, 1, 0},
+		{"noisy", Options{ModuleWidth: 2}, 0, 0, 25},
+		{"rotated blurred noisy", Options{ModuleWidth: 3, HumanReadable: true}, 21, 1, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := photo(barcode.Image(tt.opts), tt.angle, tt.blur, tt.noise)
+			got, err := Scan(img)
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if got != data {
+				t.Errorf("Scan() = %q, want %q", got, data)
+			}
+		})
+	}
+}
+
+func TestScan_JPEG(t *testing.T) {
+	data := "|010555555555501\rINV-0042\r\r0"
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, photo(MustEncode(data).Image(Options{ModuleWidth: 3}), 8, 1, 10), &jpeg.Options{Quality: 70}); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+	img, err := jpeg.Decode(&buf)
+	if err != nil {
+		t.Fatalf("jpeg.Decode() error = %v", err)
+	}
+
+	got, err := Scan(img)
+	if err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	barcode, err := thaiqrgo.BOTBarcodeFromString(got)
+	if err != nil {
+		t.Fatalf("BOTBarcodeFromString() error = %v", err)
+	}
+	if barcode.BillerID != "010555555555501" || barcode.Ref1 != "INV-0042" {
+		t.Errorf("BOTBarcodeFromString(Scan()) = %+v", barcode)
+	}
+}
+
+func TestScan_NotFound(t *testing.T) {
+	blank := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for i := range blank.Pix {
+		blank.Pix[i] = 0xff
+	}
+	stripes := image.NewGray(image.Rect(0, 0, 200, 100))
+	for y := range 100 {
+		for x := range 200 {
+			stripes.SetGray(x, y, color.Gray{Y: uint8(255 * (x / 4 % 2))})
+		}
+	}
+
+	for name, img := range map[string]image.Image{"blank": blank, "stripes": stripes} {
+		if _, err := Scan(img); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Scan(%s) error = %v, want %v", name, err, ErrNotFound)
+		}
+	}
+}
+
+func TestDecodeSymbols(t *testing.T) {
+	tests := []struct {
+		symbols []int
+		want    string
+		wantErr bool
+	}{
+		{[]int{startC, 12, 34, 56, 44, stop}, "123456", false},
+		{[]int{startB, 65, shift, 77, 66, 36, stop}, "a\rb", false},
+		{[]int{startC, 12, 34, 56, 45, stop}, "", true},
+		{[]int{startC, 12, 34, 56, 44}, "", true},
+		{[]int{startB, 102, 3, stop}, "", true},
+	}
+	for _, tt := range tests {
+		got, err := decodeSymbols(tt.symbols)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("decodeSymbols(%v) = %q, %v, want %q", tt.symbols, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package thaiqrgo
+
+// CrossBorderMerchant is the merchant data of a PromptPay cross-border merchant
+// QR code, as paid by foreign banking apps through the regional QR linkages.
+type CrossBorderMerchant struct {
+	// AcquirerID is the 3-digit bank code of the acquiring bank (Tag 30.01)
+	AcquirerID string
+
+	// MerchantID is the 15-digit merchant ID assigned by the acquirer (Tag 30.02)
+	MerchantID string
+
+	// TerminalID is the terminal ID (Tag 30.03, optional)
+	TerminalID string
+
+	// MCC is the merchant category code (Tag 52)
+	MCC string
+
+	// Name is the merchant name (Tag 59)
+	Name string
+
+	// City is the merchant city (Tag 60)
+	City string
+}
+
+// CrossBorderMerchant returns the merchant data if the QR code is a PromptPay
+// cross-border merchant QR code (Tag 30 with the cross-border AID).
+//
+// Returns false for other QR codes. Missing mandatory fields are reported as
+// warnings by the parser, see Diagnostics.
+func (q *EMVCoQR) CrossBorderMerchant() (*CrossBorderMerchant, bool) {
+	sub := q.templateTags("30")
+	if subTagValue(sub, "00") != aidPromptPayCrossBorder {
+		return nil, false
+	}
+	return &CrossBorderMerchant{
+		AcquirerID: subTagValue(sub, "01"),
+		MerchantID: subTagValue(sub, "02"),
+		TerminalID: subTagValue(sub, "03"),
+		MCC:        q.GetTagValue("52", ""),
+		Name:       q.GetTagValue("59", ""),
+		City:       q.GetTagValue("60", ""),
+	}, true
+}
+
+// templateTags returns the sub-tags of a template tag, decoding them if needed.
+func (q *EMVCoQR) templateTags(id string) []TLVTag {
+	tag := q.GetTag(id, "")
+	if tag == nil {
+		return nil
+	}
+	if len(tag.SubTags) > 0 {
+		return tag.SubTags
+	}
+	sub, _ := Decode(tag.Value)
+	return sub
+}
@@ -0,0 +1,80 @@
+package thaiqrgo
+
+import "testing"
+
+func TestEMVCoQR_CrossBorderMerchant(t *testing.T) {
+	template := Encode([]TLVTag{
+		Tag("00", aidPromptPayCrossBorder),
+		Tag("01", "004"),
+		Tag("02", "000000123456789"),
+	})
+	payload := WithCRCTag(Encode([]TLVTag{
+		Tag("00", "01"),
+		Tag("01", "11"),
+		Tag("30", template),
+		Tag("52", "5812"),
+		Tag("53", "764"),
+		Tag("58", "TH"),
+		Tag("59", "MY CAFE"),
+		Tag("60", "BANGKOK"),
+	}), "63", true)
+
+	qr, err := Parse(payload, true, false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	merchant, ok := qr.CrossBorderMerchant()
+	if !ok {
+		t.Fatal("CrossBorderMerchant() ok = false, want true")
+	}
+	want := CrossBorderMerchant{AcquirerID: "004", MerchantID: "000000123456789", MCC: "5812", Name: "MY CAFE", City: "BANGKOK"}
+	if *merchant != want {
+		t.Errorf("CrossBorderMerchant() = %+v, want %+v", *merchant, want)
+	}
+	if warnings := qr.Diagnostics().Warnings(); len(warnings) != 0 {
+		t.Errorf("Diagnostics().Warnings() = %v, want none", warnings)
+	}
+}
+
+func TestParse_CrossBorderLint(t *testing.T) {
+	template := Encode([]TLVTag{
+		Tag("00", aidPromptPayCrossBorder),
+		Tag("01", "999"),
+	})
+	payload := WithCRCTag(Encode([]TLVTag{
+		Tag("00", "01"),
+		Tag("01", "11"),
+		Tag("30", template),
+		Tag("52", "0000"),
+		Tag("53", "840"),
+		Tag("58", "TH"),
+	}), "63", true)
+
+	qr, err := Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := map[string]bool{}
+	for _, w := range qr.Diagnostics().Warnings() {
+		got[w.Path] = true
+	}
+	for _, path := range []string{"52", "53", "59", "60"} {
+		if !got[path] {
+			t.Errorf("Diagnostics().Warnings() has no warning for %s: %v", path, qr.Diagnostics().Warnings())
+		}
+	}
+	for _, path := range []string{"30.01", "30.02", "58"} {
+		if got[path] {
+			t.Errorf("Diagnostics().Warnings() has a warning for %s: %v", path, qr.Diagnostics().Warnings())
+		}
+	}
+
+	domestic, err := Parse("00020101021129370016A000000677010111011300668012345675802TH530376463046197", true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, ok := domestic.CrossBorderMerchant(); ok {
+		t.Error("CrossBorderMerchant() ok = true for a domestic QR, want false")
+	}
+}
@@ -0,0 +1,103 @@
+package thaiqrgo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity tells whether a diagnostic makes the payload invalid.
+type Severity int
+
+// Diagnostic severities.
+const (
+	// SeverityError is a problem that makes the payload invalid
+	SeverityError Severity = iota
+
+	// SeverityWarning is a valid but suspicious value, e.g. a national ID
+	// with a wrong check digit
+	SeverityWarning
+)
+
+// String implements the fmt.Stringer interface.
+func (s Severity) String() string {
+	if s == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic describes a single problem found while parsing a QR code payload.
+type Diagnostic struct {
+	// Severity is the severity of the problem (default: SeverityError)
+	Severity Severity
+
+	// Offset is the byte position in the payload where the problem was found,
+	// or -1 if it does not apply to a specific position
+	Offset int
+
+	// Path is the tag path the problem refers to (e.g. "29" or "29.01"), if any
+	Path string
+
+	// Message describes the problem
+	Message string
+}
+
+// Error implements the error interface.
+func (d Diagnostic) Error() string {
+	var b strings.Builder
+	if d.Severity == SeverityWarning {
+		b.WriteString("warning: ")
+	}
+	if d.Path != "" {
+		b.WriteString("tag ")
+		b.WriteString(d.Path)
+		b.WriteString(": ")
+	}
+	b.WriteString(d.Message)
+	if d.Offset >= 0 {
+		fmt.Fprintf(&b, " (at position %d)", d.Offset)
+	}
+	return b.String()
+}
+
+// Diagnostics is a list of problems found in a payload.
+//
+// It implements the error interface so that lenient parsing can return
+// every problem at once.
+type Diagnostics []Diagnostic
+
+// Error implements the error interface.
+func (d Diagnostics) Error() string {
+	switch len(d) {
+	case 0:
+		return "no diagnostics"
+	case 1:
+		return d[0].Error()
+	}
+
+	messages := make([]string, len(d))
+	for i := range d {
+		messages[i] = d[i].Error()
+	}
+	return fmt.Sprintf("%d problems: %s", len(d), strings.Join(messages, "; "))
+}
+
+// Errors returns the diagnostics with SeverityError.
+func (d Diagnostics) Errors() Diagnostics {
+	return d.filter(SeverityError)
+}
+
+// Warnings returns the diagnostics with SeverityWarning.
+func (d Diagnostics) Warnings() Diagnostics {
+	return d.filter(SeverityWarning)
+}
+
+func (d Diagnostics) filter(severity Severity) Diagnostics {
+	var result Diagnostics
+	for _, diagnostic := range d {
+		if diagnostic.Severity == severity {
+			result = append(result, diagnostic)
+		}
+	}
+	return result
+}
@@ -0,0 +1,215 @@
+package dictionary
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Language selects the language of a tag name.
+type Language string
+
+// Supported languages.
+const (
+	English Language = "en"
+	Thai    Language = "th"
+)
+
+// Specifications the tags are defined in.
+const (
+	SpecEMVCo       = "EMVCo QR Code Specification for Payment Systems (Merchant-Presented Mode)"
+	SpecThaiQR      = "Thai QR Payment Standard"
+	SpecSlipVerify  = "Slip Verify API Mini QR Data"
+	SpecTrueMoney   = "TrueMoney Wallet"
+	SpecPromptPayBP = "PromptPay Bill Payment"
+)
+
+// Entry describes a tag.
+type Entry struct {
+	// Parent is the path of the parent tag ("" for root tags)
+	Parent string
+
+	// ID is the tag ID
+	ID string
+
+	// NameEN is the English name
+	NameEN string
+
+	// NameTH is the Thai name
+	NameTH string
+
+	// Description explains the tag value
+	Description string
+
+	// SpecRef is the specification the tag is defined in
+	SpecRef string
+}
+
+// Path returns the full tag path (e.g. "29.01").
+func (e Entry) Path() string {
+	return joinPath(e.Parent, e.ID)
+}
+
+// Name returns the tag name in the given language, falling back to English.
+func (e Entry) Name(lang Language) string {
+	if lang == Thai && e.NameTH != "" {
+		return e.NameTH
+	}
+	return e.NameEN
+}
+
+var entries = map[string]Entry{}
+
+func init() {
+	for _, e := range builtin {
+		entries[e.Path()] = e
+	}
+}
+
+var builtin = []Entry{
+	// Root tags
+	{"", "00", "Payload Format Indicator", "ตัวระบุรูปแบบข้อมูล", "Version of the QR code payload format, always \"01\"", SpecEMVCo},
+	{"", "01", "Point of Initiation", "วิธีการเริ่มต้นรายการ", "\"11\" for a static QR code, \"12\" for a dynamic QR code", SpecEMVCo},
+	{"", "29", "Merchant Information", "ข้อมูลบัญชีผู้รับเงิน (พร้อมเพย์)", "PromptPay AnyID credit transfer template", SpecThaiQR},
+	{"", "30", "Merchant Information (Bill Payment)", "ข้อมูลการชำระบิล", "PromptPay Bill Payment template", SpecThaiQR},
+	{"", "51", "Country Code (Slip Verify)", "รหัสประเทศ (ตรวจสอบสลิป)", "Country code in Slip Verify QR codes", SpecSlipVerify},
+	{"", "52", "Merchant Category Code", "รหัสประเภทร้านค้า", "ISO 18245 merchant category code", SpecEMVCo},
+	{"", "53", "Transaction Currency", "สกุลเงิน", "ISO 4217 numeric currency code, \"764\" for THB", SpecEMVCo},
+	{"", "54", "Transaction Amount", "จำนวนเงิน", "Amount in decimal notation, e.g. \"100.00\"", SpecEMVCo},
+	{"", "55", "Tip or Convenience Indicator", "ตัวระบุทิปหรือค่าธรรมเนียม", "Whether the payer is prompted for a tip or charged a fee", SpecEMVCo},
+	{"", "56", "Value of Convenience Fee Fixed", "ค่าธรรมเนียมแบบคงที่", "Fixed convenience fee amount", SpecEMVCo},
+	{"", "57", "Value of Convenience Fee Percentage", "ค่าธรรมเนียมแบบร้อยละ", "Convenience fee as a percentage of the amount", SpecEMVCo},
+	{"", "58", "Country Code", "รหัสประเทศ", "ISO 3166-1 alpha-2 country code, \"TH\" for Thailand", SpecEMVCo},
+	{"", "59", "Merchant Name", "ชื่อร้านค้า", "Name of the merchant", SpecEMVCo},
+	{"", "60", "Merchant City", "เมืองที่ตั้งร้านค้า", "City of the merchant", SpecEMVCo},
+	{"", "61", "Postal Code", "รหัสไปรษณีย์", "Postal code of the merchant", SpecEMVCo},
+	{"", "62", "Additional Data Field Template", "ข้อมูลเพิ่มเติม", "Bill number, references and other additional data", SpecEMVCo},
+	{"", "63", "CRC", "รหัสตรวจสอบความถูกต้อง (CRC)", "CRC-16/XMODEM checksum of the payload", SpecEMVCo},
+	{"", "64", "Merchant Information - Language Template", "ข้อมูลร้านค้าภาษาอื่น", "Merchant name and city in an alternate language", SpecEMVCo},
+	{"", "81", "Personal Message", "ข้อความถึงผู้รับ", "UTF-16 hex encoded message shown by TrueMoney Wallet", SpecTrueMoney},
+	{"", "91", "CRC (Slip Verify)", "รหัสตรวจสอบความถูกต้อง (ตรวจสอบสลิป)", "CRC-16/XMODEM checksum of a Slip Verify payload", SpecSlipVerify},
+
+	// Sub-tags of Tag 00 (Slip Verify / TrueMoney Slip Verify)
+	{"00", "00", "API Type", "ประเภท API", "\"000001\" for Slip Verify, \"01\" for TrueMoney Slip Verify", SpecSlipVerify},
+	{"00", "01", "Sending Bank / API Type 01", "ธนาคารผู้โอน / ประเภท API 01", "Sending bank code, or \"01\" for TrueMoney Slip Verify", SpecSlipVerify},
+	{"00", "02", "Transaction Reference / Event Type", "เลขอ้างอิงรายการ / ประเภทรายการ", "Transaction reference, or the TrueMoney event type (e.g. \"P2P\")", SpecSlipVerify},
+	{"00", "03", "Transaction ID", "รหัสรายการ", "TrueMoney transaction ID", SpecTrueMoney},
+	{"00", "04", "Date (DDMMYYYY)", "วันที่ (DDMMYYYY)", "TrueMoney transaction date", SpecTrueMoney},
+
+	// Sub-tags of Tag 29 (PromptPay AnyID)
+	{"29", "00", "GUID (A000000677010111)", "รหัสแอปพลิเคชัน (A000000677010111)", "PromptPay AnyID application identifier", SpecThaiQR},
+	{"29", "01", "MSISDN (Mobile Number)", "หมายเลขโทรศัพท์มือถือ", "Mobile number as a 13-digit proxy, e.g. \"0066812345678\"", SpecThaiQR},
+	{"29", "02", "NATID (National/Tax ID)", "เลขประจำตัวประชาชน/เลขประจำตัวผู้เสียภาษี", "13-digit national ID or tax ID", SpecThaiQR},
+	{"29", "03", "EWALLETID (E-Wallet ID)", "หมายเลขกระเป๋าเงินอิเล็กทรอนิกส์", "15-digit e-wallet ID", SpecThaiQR},
+	{"29", "04", "BANKACC (Bank Account - Reserved)", "เลขที่บัญชีธนาคาร", "Bank code and account number", SpecThaiQR},
+
+	// Sub-tags of Tag 30 (PromptPay Bill Payment)
+	{"30", "00", "GUID (A000000677010112)", "รหัสแอปพลิเคชัน (A000000677010112)", "PromptPay Bill Payment application identifier", SpecPromptPayBP},
+	{"30", "01", "Biller ID", "รหัสผู้ให้บริการเรียกเก็บเงิน", "Tax ID of the biller followed by a 2-digit suffix", SpecPromptPayBP},
+	{"30", "02", "Reference 1", "หมายเลขอ้างอิง 1", "Customer or invoice reference", SpecPromptPayBP},
+	{"30", "03", "Reference 2", "หมายเลขอ้างอิง 2", "Secondary reference", SpecPromptPayBP},
+
+	// Sub-tags of Tag 62 (Additional Data Field Template)
+	{"62", "01", "Bill Number", "เลขที่ใบแจ้งหนี้", "Invoice or bill number", SpecEMVCo},
+	{"62", "02", "Mobile Number", "หมายเลขโทรศัพท์มือถือ", "Mobile number for top-ups or bill payments", SpecEMVCo},
+	{"62", "03", "Store Label", "รหัสสาขา", "Store or branch identifier", SpecEMVCo},
+	{"62", "04", "Loyalty Number", "หมายเลขสมาชิก", "Loyalty card number", SpecEMVCo},
+	{"62", "05", "Reference Label", "หมายเลขอ้างอิง", "Transaction reference defined by the merchant", SpecEMVCo},
+	{"62", "06", "Customer Label", "รหัสลูกค้า", "Customer identifier", SpecEMVCo},
+	{"62", "07", "Reference 3", "หมายเลขอ้างอิง 3", "Terminal label, used as Reference 3 in Thai bill payments", SpecThaiQR},
+	{"62", "08", "Purpose of Transaction", "วัตถุประสงค์ของรายการ", "Purpose of the transaction", SpecEMVCo},
+	{"62", "09", "Additional Consumer Data Request", "ข้อมูลเพิ่มเติมที่ขอจากผู้ชำระเงิน", "Data the app should ask the payer for", SpecEMVCo},
+
+	// Sub-tags of Tag 64 (Merchant Information - Language Template)
+	{"64", "00", "Language Preference", "ภาษา", "ISO 639 two-letter language code", SpecEMVCo},
+	{"64", "01", "Merchant Name - Alternate Language", "ชื่อร้านค้า (ภาษาอื่น)", "Merchant name in the preferred language", SpecEMVCo},
+	{"64", "02", "Merchant City - Alternate Language", "เมืองที่ตั้งร้านค้า (ภาษาอื่น)", "Merchant city in the preferred language", SpecEMVCo},
+}
+
+// Lookup returns the entry for tag id under the given parent path ("" for root tags).
+//
+// Tags without a dedicated entry are matched against the ranges EMVCo reserves
+// (e.g. merchant account information 02-51 or unreserved templates 80-99).
+func Lookup(parent, id string) (Entry, bool) {
+	if e, ok := entries[joinPath(parent, id)]; ok {
+		return e, true
+	}
+	return lookupRange(parent, id)
+}
+
+// Name returns the name of tag id under the given parent path in the given language.
+//
+// Unknown tags are named "Tag XX" (root) or "Sub Tag XX" (sub-tags).
+func Name(parent, id string, lang Language) string {
+	if e, ok := Lookup(parent, id); ok {
+		return e.Name(lang)
+	}
+
+	switch {
+	case lang == Thai && parent == "":
+		return fmt.Sprintf("แท็ก %s", id)
+	case lang == Thai:
+		return fmt.Sprintf("แท็กย่อย %s", id)
+	case parent == "":
+		return fmt.Sprintf("Tag %s", id)
+	default:
+		return fmt.Sprintf("Sub Tag %s", id)
+	}
+}
+
+// Entries returns all dedicated entries sorted by path.
+func Entries() []Entry {
+	result := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, e)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Path() < result[j].Path()
+	})
+	return result
+}
+
+// lookupRange matches tags against the ranges reserved by EMVCo.
+func lookupRange(parent, id string) (Entry, bool) {
+	e := Entry{Parent: parent, ID: id, SpecRef: SpecEMVCo}
+
+	switch {
+	case parent == "" && id >= "02" && id <= "51":
+		e.NameEN, e.NameTH = "Merchant Account Information", "ข้อมูลบัญชีร้านค้า"
+		e.Description = "Payment network template identified by its GUID (sub-tag 00)"
+	case parent == "" && id >= "65" && id <= "79":
+		e.NameEN, e.NameTH = "RFU for EMVCo", "สงวนไว้สำหรับ EMVCo"
+		e.Description = "Reserved for future use"
+	case parent == "" && id >= "80" && id <= "99":
+		e.NameEN, e.NameTH = "Unreserved Template", "เทมเพลตอิสระ"
+		e.Description = "Template defined by the QR code issuer"
+	case parent == "62" && id >= "10" && id <= "49":
+		e.NameEN, e.NameTH = "RFU for EMVCo", "สงวนไว้สำหรับ EMVCo"
+		e.Description = "Reserved for future use"
+	case parent == "62" && id >= "50" && id <= "99":
+		e.NameEN, e.NameTH = "Payment System Specific Template", "เทมเพลตเฉพาะระบบการชำระเงิน"
+		e.Description = "Template defined by a payment system, identified by its GUID (sub-tag 00)"
+	case id == "00" && isTemplatePath(parent):
+		e.NameEN, e.NameTH = "Globally Unique Identifier", "รหัสระบุเฉพาะ"
+		e.Description = "AID, UUID or reverse domain name identifying the template"
+	default:
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// isTemplatePath reports whether parent is a template that starts with a GUID.
+func isTemplatePath(parent string) bool {
+	last := parent[strings.LastIndex(parent, ".")+1:]
+	if parent == last {
+		return (last >= "02" && last <= "51") || (last >= "80" && last <= "99")
+	}
+	return strings.HasPrefix(parent, "62.") && last >= "50" && last <= "99"
+}
+
+func joinPath(parent, id string) string {
+	if parent == "" {
+		return id
+	}
+	return parent + "." + id
+}
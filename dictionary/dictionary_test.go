@@ -0,0 +1,70 @@
+package dictionary
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		parent, id string
+		wantEN     string
+		wantTH     string
+	}{
+		{"", "54", "Transaction Amount", "จำนวนเงิน"},
+		{"29", "01", "MSISDN (Mobile Number)", "หมายเลขโทรศัพท์มือถือ"},
+		{"62", "07", "Reference 3", "หมายเลขอ้างอิง 3"},
+		{"", "31", "Merchant Account Information", "ข้อมูลบัญชีร้านค้า"},
+		{"", "85", "Unreserved Template", "เทมเพลตอิสระ"},
+		{"62", "50", "Payment System Specific Template", "เทมเพลตเฉพาะระบบการชำระเงิน"},
+		{"62.50", "00", "Globally Unique Identifier", "รหัสระบุเฉพาะ"},
+		{"31", "00", "Globally Unique Identifier", "รหัสระบุเฉพาะ"},
+	}
+
+	for _, tt := range tests {
+		e, ok := Lookup(tt.parent, tt.id)
+		if !ok {
+			t.Errorf("Lookup(%q, %q) not found", tt.parent, tt.id)
+			continue
+		}
+		if e.Name(English) != tt.wantEN {
+			t.Errorf("Lookup(%q, %q) English = %v, want %v", tt.parent, tt.id, e.Name(English), tt.wantEN)
+		}
+		if e.Name(Thai) != tt.wantTH {
+			t.Errorf("Lookup(%q, %q) Thai = %v, want %v", tt.parent, tt.id, e.Name(Thai), tt.wantTH)
+		}
+		if e.SpecRef == "" || e.Description == "" {
+			t.Errorf("Lookup(%q, %q) = %+v, want description and spec reference", tt.parent, tt.id, e)
+		}
+	}
+
+	if _, ok := Lookup("29", "99"); ok {
+		t.Error("Lookup(29, 99) should not be found")
+	}
+}
+
+func TestName_Fallback(t *testing.T) {
+	if got := Name("", "70", English); got != "RFU for EMVCo" {
+		t.Errorf("Name(70) = %v, want RFU for EMVCo", got)
+	}
+	if got := Name("29", "99", English); got != "Sub Tag 99" {
+		t.Errorf("Name(29, 99) = %v, want Sub Tag 99", got)
+	}
+	if got := Name("29", "99", Thai); got != "แท็กย่อย 99" {
+		t.Errorf("Name(29, 99) Thai = %v, want แท็กย่อย 99", got)
+	}
+}
+
+func TestEntries(t *testing.T) {
+	all := Entries()
+	if len(all) == 0 {
+		t.Fatal("Entries() returned nothing")
+	}
+	for i := 1; i < len(all); i++ {
+		if all[i-1].Path() >= all[i].Path() {
+			t.Errorf("Entries() not sorted: %v before %v", all[i-1].Path(), all[i].Path())
+		}
+	}
+	for _, e := range all {
+		if e.NameEN == "" || e.NameTH == "" {
+			t.Errorf("Entries() %v is missing a name", e.Path())
+		}
+	}
+}
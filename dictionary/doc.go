@@ -0,0 +1,7 @@
+// Package dictionary provides human-readable names for Thai QR code tags.
+//
+// Each tag is identified by its parent path ("" for root tags, "29" for
+// sub-tags of Tag 29, "62.50" for sub-tags of Tag 62.50) and its ID.
+// Names are available in English and Thai, together with a short
+// description and the specification the tag comes from.
+package dictionary
@@ -0,0 +1,66 @@
+package thaiqrgo
+
+import "time"
+
+// Dynamic QR code data in the Additional Data Field Template (Tag 62).
+const (
+	// ExpiryGUID identifies the payment system specific template (Tag 62.50)
+	// that holds the expiry time of a dynamic QR code
+	ExpiryGUID = "com.github.klimakov.thaiqrgo.expiry"
+
+	// ExpiryLayout is the time.Parse layout of the expiry time (UTC, sub-tag 01)
+	ExpiryLayout = "20060102150405"
+)
+
+// IsDynamic reports whether the QR code is dynamic (Tag 01 is "12"),
+// i.e. meant for a single transaction.
+func (q *EMVCoQR) IsDynamic() bool {
+	return q.GetTagValue("01", "") == "12"
+}
+
+// ReferenceLabel returns the order or transaction reference (Tag 62.05).
+//
+// Returns an empty string if the QR code has no reference label.
+func (q *EMVCoQR) ReferenceLabel() string {
+	return subTagValue(q.templateTags("62"), "05")
+}
+
+// ExpiresAt returns the expiry time of a dynamic QR code, read from the
+// payment system specific template identified by ExpiryGUID (Tag 62.50-99).
+//
+// Returns false if the QR code has no expiry or the expiry time is malformed.
+func (q *EMVCoQR) ExpiresAt() (time.Time, bool) {
+	tag, ok := expiryTemplate(q.templateTags("62"))
+	if !ok {
+		return time.Time{}, false
+	}
+	sub, _ := Decode(tag.Value)
+	t, err := time.Parse(ExpiryLayout, subTagValue(sub, "01"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// expiryTemplate returns the template identified by ExpiryGUID among the
+// sub-tags of the Additional Data Field Template (Tag 62).
+func expiryTemplate(additional []TLVTag) (TLVTag, bool) {
+	for _, tag := range additional {
+		if tag.ID < "50" || tag.ID > "99" {
+			continue
+		}
+		sub, err := Decode(tag.Value)
+		if err == nil && subTagValue(sub, "00") == ExpiryGUID {
+			return tag, true
+		}
+	}
+	return TLVTag{}, false
+}
+
+// Expired reports whether the QR code has expired at the given time.
+//
+// QR codes without an expiry never expire.
+func (q *EMVCoQR) Expired(now time.Time) bool {
+	expiresAt, ok := q.ExpiresAt()
+	return ok && !now.Before(expiresAt)
+}
@@ -0,0 +1,79 @@
+package thaiqrgo
+
+import (
+	"testing"
+	"time"
+)
+
+func dynamicPayload(initiation, additionalData string) string {
+	tags := []TLVTag{
+		Tag("00", "01"),
+		Tag("01", initiation),
+		Tag("29", Encode([]TLVTag{Tag("00", "A000000677010111"), Tag("01", "0066812345678")})),
+		Tag("53", "764"),
+		Tag("58", "TH"),
+	}
+	if additionalData != "" {
+		tags = append(tags, Tag("62", additionalData))
+	}
+	return WithCRCTag(Encode(tags), "63", true)
+}
+
+func TestEMVCoQR_Expiry(t *testing.T) {
+	additionalData := Encode([]TLVTag{
+		Tag("05", "ORDER-1001"),
+		Tag("50", Encode([]TLVTag{Tag("00", ExpiryGUID), Tag("01", "20250301103000")})),
+	})
+	qr, err := Parse(dynamicPayload("12", additionalData), true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !qr.IsDynamic() {
+		t.Error("IsDynamic() = false, want true")
+	}
+	if got := qr.ReferenceLabel(); got != "ORDER-1001" {
+		t.Errorf("ReferenceLabel() = %v, want %v", got, "ORDER-1001")
+	}
+
+	want := time.Date(2025, time.March, 1, 10, 30, 0, 0, time.UTC)
+	got, ok := qr.ExpiresAt()
+	if !ok || !got.Equal(want) {
+		t.Errorf("ExpiresAt() = %v, %v, want %v, true", got, ok, want)
+	}
+	if qr.Expired(want.Add(-time.Second)) {
+		t.Error("Expired() before expiry = true, want false")
+	}
+	if !qr.Expired(want) {
+		t.Error("Expired() at expiry = false, want true")
+	}
+}
+
+func TestEMVCoQR_NoExpiry(t *testing.T) {
+	tests := []struct {
+		name           string
+		additionalData string
+	}{
+		{"no tag 62", ""},
+		{"other GUID", Encode([]TLVTag{Tag("50", Encode([]TLVTag{Tag("00", "com.example"), Tag("01", "20250301103000")}))})},
+		{"malformed time", Encode([]TLVTag{Tag("50", Encode([]TLVTag{Tag("00", ExpiryGUID), Tag("01", "2025-03-01")}))})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qr, err := Parse(dynamicPayload("11", tt.additionalData), true, false)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if _, ok := qr.ExpiresAt(); ok {
+				t.Error("ExpiresAt() ok = true, want false")
+			}
+			if qr.Expired(time.Now()) {
+				t.Error("Expired() = true, want false")
+			}
+			if qr.IsDynamic() {
+				t.Error("IsDynamic() = true, want false")
+			}
+		})
+	}
+}
@@ -2,8 +2,9 @@ package thaiqrgo
 
 // EMVCoQR represents a parsed EMVCo QR code with its TLV tags.
 type EMVCoQR struct {
-	payload string
-	tags    []TLVTag
+	payload     string
+	tags        []TLVTag
+	diagnostics Diagnostics
 }
 
 // GetTag retrieves a tag or sub-tag by ID.
@@ -35,6 +36,12 @@ func (q *EMVCoQR) GetPayload() string {
 	return q.payload
 }
 
+// Diagnostics returns the problems found by a lenient ParseWithOptions call,
+// and the warnings about suspicious values found by any parse call.
+func (q *EMVCoQR) Diagnostics() Diagnostics {
+	return q.diagnostics
+}
+
 // Validate validates the QR code payload by recalculating the CRC checksum.
 //
 // It filters out the CRC tag, recalculates the checksum, and compares it with the original payload.
@@ -0,0 +1,6 @@
+// Package ewallet provides a registry of PromptPay e-wallet providers.
+//
+// E-wallet IDs (Tag 29.03) are 15 digits. The first three identify the
+// provider and the rest follow the provider's own layout; TrueMoney Wallet,
+// for example, uses "14000" followed by the 10-digit mobile number.
+package ewallet
@@ -0,0 +1,186 @@
+package ewallet
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/klimakov/thai-qr-go/phone"
+)
+
+// IDLength is the number of digits of an e-wallet ID.
+const IDLength = 15
+
+// CodeTrueMoney is the provider code of TrueMoney Wallet.
+const CodeTrueMoney = "140"
+
+// Provider describes an e-wallet provider and its ID layout.
+type Provider struct {
+	// Code is the 3-digit provider code the ID starts with
+	Code string
+
+	// NameEN is the English name
+	NameEN string
+
+	// NameTH is the Thai name
+	NameTH string
+
+	// Prefix is the fixed start of every ID of the provider, including Code
+	// (e.g. "14000" for TrueMoney). The account fills the rest of the 15 digits.
+	Prefix string
+
+	// ParseAccount is an optional rule that validates an account and returns
+	// it in the form stored in the ID, e.g. a mobile number in local form.
+	// Without it, the account must be the digits after Prefix.
+	ParseAccount func(account string) (string, error)
+}
+
+// TrueMoney is the TrueMoney Wallet provider. Accounts are mobile numbers.
+var TrueMoney = Provider{
+	Code:   CodeTrueMoney,
+	NameEN: "TrueMoney Wallet",
+	NameTH: "ทรูมันนี่ วอลเล็ท",
+	Prefix: CodeTrueMoney + "00",
+	ParseAccount: func(account string) (string, error) {
+		number, err := phone.Parse(account)
+		if err != nil {
+			return "", err
+		}
+		return number.Local(), nil
+	},
+}
+
+var (
+	mu       sync.RWMutex
+	registry = map[string]Provider{TrueMoney.Code: TrueMoney}
+)
+
+// Lookup returns the provider with the given 3-digit code.
+func Lookup(code string) (Provider, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := registry[code]
+	return p, ok
+}
+
+// All returns every registered provider, sorted by code.
+func All() []Provider {
+	mu.RLock()
+	defer mu.RUnlock()
+	providers := make([]Provider, 0, len(registry))
+	for _, p := range registry {
+		providers = append(providers, p)
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i].Code < providers[j].Code })
+	return providers
+}
+
+// Register adds a provider to the registry, or replaces the provider with the same code.
+//
+// Returns an error if the code is not 3 digits or Prefix does not start with it.
+func Register(p Provider) error {
+	if len(p.Code) != 3 || !isDigits(p.Code) {
+		return fmt.Errorf("invalid e-wallet provider code: %q", p.Code)
+	}
+	if p.Prefix == "" {
+		p.Prefix = p.Code
+	}
+	if !strings.HasPrefix(p.Prefix, p.Code) || len(p.Prefix) >= IDLength || !isDigits(p.Prefix) {
+		return fmt.Errorf("invalid e-wallet provider %s: bad prefix %q", p.Code, p.Prefix)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	registry[p.Code] = p
+	return nil
+}
+
+// ID returns the 15-digit e-wallet ID of an account.
+//
+// Returns an error if the account does not fit the provider's layout.
+func (p Provider) ID(account string) (string, error) {
+	if p.ParseAccount != nil {
+		parsed, err := p.ParseAccount(account)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s account: %w", p.NameEN, err)
+		}
+		account = parsed
+	}
+
+	id := p.Prefix + account
+	if len(id) != IDLength || !isDigits(account) {
+		return "", fmt.Errorf("invalid %s account: must be %d digits", p.NameEN, IDLength-len(p.Prefix))
+	}
+	return id, nil
+}
+
+// Wallet is an e-wallet account, as carried by a PromptPay EWALLETID proxy.
+type Wallet struct {
+	// Provider is the e-wallet provider
+	Provider Provider
+
+	// Account is the account part of the ID (e.g. the mobile number for TrueMoney)
+	Account string
+}
+
+// Parse parses a 15-digit e-wallet ID into its provider and account.
+//
+// Returns an error if the ID is malformed, the provider is unknown,
+// or the account does not fit the provider's layout.
+func Parse(id string) (Wallet, error) {
+	if err := ValidateFormat(id); err != nil {
+		return Wallet{}, err
+	}
+
+	p, ok := Lookup(id[:3])
+	if !ok {
+		return Wallet{}, fmt.Errorf("unknown e-wallet provider code: %q", id[:3])
+	}
+	if !strings.HasPrefix(id, p.Prefix) {
+		return Wallet{}, fmt.Errorf("invalid %s ID: must start with %s", p.NameEN, p.Prefix)
+	}
+
+	account := id[len(p.Prefix):]
+	if _, err := p.ID(account); err != nil {
+		return Wallet{}, err
+	}
+	return Wallet{Provider: p, Account: account}, nil
+}
+
+// Validate checks that id is a valid e-wallet ID of a known provider.
+func Validate(id string) error {
+	_, err := Parse(id)
+	return err
+}
+
+// ValidateFormat checks that id is a well-formed 15-digit e-wallet ID.
+//
+// Unlike Validate, it accepts providers missing from the registry.
+func ValidateFormat(id string) error {
+	if len(id) != IDLength || !isDigits(id) {
+		return fmt.Errorf("invalid e-wallet ID: must be %d digits", IDLength)
+	}
+	return nil
+}
+
+// ID returns the 15-digit e-wallet ID.
+func (w Wallet) ID() string {
+	return w.Provider.Prefix + w.Account
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Returns the provider name and the account (e.g. "TrueMoney Wallet 0812345678").
+func (w Wallet) String() string {
+	return w.Provider.NameEN + " " + w.Account
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,92 @@
+package ewallet
+
+import "testing"
+
+func TestTrueMoney_ID(t *testing.T) {
+	for _, account := range []string{"0801111111", "080-111-1111", "+66 80 111 1111"} {
+		got, err := TrueMoney.ID(account)
+		if err != nil {
+			t.Errorf("TrueMoney.ID(%q) error = %v", account, err)
+			continue
+		}
+		if got != "140000801111111" {
+			t.Errorf("TrueMoney.ID(%q) = %v, want 140000801111111", account, got)
+		}
+	}
+
+	for _, account := range []string{"", "080111111", "0201111111"} {
+		if _, err := TrueMoney.ID(account); err == nil {
+			t.Errorf("TrueMoney.ID(%q) should return error", account)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	wallet, err := Parse("140000801111111")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if wallet.Provider.Code != CodeTrueMoney || wallet.Account != "0801111111" {
+		t.Errorf("Parse() = %v/%v, want 140/0801111111", wallet.Provider.Code, wallet.Account)
+	}
+	if wallet.ID() != "140000801111111" {
+		t.Errorf("Wallet.ID() = %v, want 140000801111111", wallet.ID())
+	}
+
+	tests := []string{
+		"",
+		"14000080111111",
+		"14000080111111X",
+		"012345678901234", // unknown provider
+		"140010801111111", // wrong TrueMoney prefix
+		"140000201111111", // not a mobile number
+	}
+	for _, id := range tests {
+		if err := Validate(id); err == nil {
+			t.Errorf("Validate(%q) should return error", id)
+		}
+	}
+
+	for _, id := range []string{"012345678901234", "140010801111111"} {
+		if err := ValidateFormat(id); err != nil {
+			t.Errorf("ValidateFormat(%q) error = %v", id, err)
+		}
+	}
+	for _, id := range []string{"", "14000080111111", "14000080111111X"} {
+		if err := ValidateFormat(id); err == nil {
+			t.Errorf("ValidateFormat(%q) should return error", id)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register(Provider{Code: "901", NameEN: "Test Wallet", Prefix: "9019"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	defer func() {
+		mu.Lock()
+		delete(registry, "901")
+		mu.Unlock()
+	}()
+
+	id, err := Parse("901912345678901")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if id.Account != "12345678901" || id.Provider.NameEN != "Test Wallet" {
+		t.Errorf("Parse() = %v, want Test Wallet 12345678901", id)
+	}
+	if _, err := id.Provider.ID("123"); err == nil {
+		t.Error("Provider.ID() should return error for short account")
+	}
+
+	if len(All()) != 2 {
+		t.Errorf("All() = %d providers, want 2", len(All()))
+	}
+
+	for _, p := range []Provider{{Code: "9"}, {Code: "902", Prefix: "903"}, {Code: "902", Prefix: "902123456789012"}} {
+		if err := Register(p); err == nil {
+			t.Errorf("Register(%+v) should return error", p)
+		}
+	}
+}
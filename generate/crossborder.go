@@ -0,0 +1,90 @@
+package generate
+
+import (
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/aid"
+	"github.com/klimakov/thai-qr-go/bank"
+)
+
+// aidCrossBorder is the application identifier of the PromptPay cross-border merchant template.
+const aidCrossBorder = aid.PromptPayCrossBorder
+
+// CrossBorderConfig configures a PromptPay cross-border merchant QR code,
+// which foreign banking apps can pay through the regional QR linkages.
+type CrossBorderConfig struct {
+	// AcquirerID is the 3-digit bank code of the acquiring bank (see package bank)
+	AcquirerID string
+
+	// MerchantID is the 15-digit merchant ID assigned by the acquirer
+	MerchantID string
+
+	// TerminalID is the terminal ID (optional, up to 8 digits or uppercase letters)
+	TerminalID string
+
+	// MCC is the ISO 18245 merchant category code (4 digits, not "0000")
+	MCC string
+
+	// Name is the merchant name shown to the payer (up to 25 characters)
+	Name string
+
+	// City is the merchant city (up to 15 characters)
+	City string
+
+	// PostalCode is the merchant postal code (optional)
+	PostalCode string
+
+	// Amount is the transaction amount in THB (optional)
+	Amount *thaiqrgo.Amount
+}
+
+// CrossBorder generates a PromptPay cross-border merchant QR code payload.
+//
+// The merchant account is a Tag 30 template with the cross-border AID holding the
+// acquirer ID (30.01), merchant ID (30.02) and terminal ID (30.03). Unlike the
+// domestic templates, the merchant category code, name and city are mandatory.
+// The currency is always THB and the country TH; foreign apps convert the amount.
+func CrossBorder(config CrossBorderConfig) (string, error) {
+	var check configCheck
+	if check.required("AcquirerID", config.AcquirerID) {
+		if _, ok := bank.Lookup(config.AcquirerID); !ok {
+			check.fail("AcquirerID", config.AcquirerID, "registered bank code")
+		}
+	}
+	if check.required("MerchantID", config.MerchantID) && (len(config.MerchantID) != 15 || !isNumeric(config.MerchantID)) {
+		check.fail("MerchantID", config.MerchantID, "15 digits")
+	}
+	check.text("TerminalID", config.TerminalID, 8, charsetUpper, isUpperAlphanumeric)
+	if check.required("MCC", config.MCC) && (len(config.MCC) != 4 || !isNumeric(config.MCC) || config.MCC == "0000") {
+		check.fail("MCC", config.MCC, "4-digit merchant category code")
+	}
+	if check.required("Name", config.Name) {
+		check.text("Name", config.Name, 25, charsetPrintable, isPrintable)
+	}
+	if check.required("City", config.City) {
+		check.text("City", config.City, 15, charsetPrintable, isPrintable)
+	}
+	check.amount("Amount", config.Amount)
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
+	fields := []thaiqrgo.TLVTag{
+		thaiqrgo.Tag("01", config.AcquirerID),
+		thaiqrgo.Tag("02", config.MerchantID),
+	}
+	if config.TerminalID != "" {
+		fields = append(fields, thaiqrgo.Tag("03", config.TerminalID))
+	}
+
+	merchant := &Merchant{
+		MCC:        config.MCC,
+		Currency:   "764",
+		Country:    "TH",
+		Amount:     config.Amount,
+		Name:       config.Name,
+		City:       config.City,
+		PostalCode: config.PostalCode,
+	}
+	merchant.AddAccount("30", aidCrossBorder, fields...)
+	return merchant.Build()
+}
@@ -0,0 +1,83 @@
+package generate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+func TestCrossBorder(t *testing.T) {
+	amount := thaiqrgo.MustParseAmount("120.00")
+	config := CrossBorderConfig{
+		AcquirerID: "004",
+		MerchantID: "000000123456789",
+		TerminalID: "T0001",
+		MCC:        "5812",
+		Name:       "MY CAFE",
+		City:       "BANGKOK",
+		Amount:     &amount,
+	}
+
+	payload, err := CrossBorder(config)
+	if err != nil {
+		t.Fatalf("CrossBorder() error = %v", err)
+	}
+	want := "00020101021230550016A000000677010113010300402150000001234567890305T0001" +
+		"5204581253037645802TH5406120.005907MY CAFE6007BANGKOK6304ED81"
+	if payload != want {
+		t.Errorf("CrossBorder() = %v, want %v", payload, want)
+	}
+
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if warnings := qr.Diagnostics().Warnings(); len(warnings) != 0 {
+		t.Errorf("Parse() warnings = %v, want none", warnings)
+	}
+
+	got, err := CrossBorderConfigFromQR(qr)
+	if err != nil {
+		t.Fatalf("CrossBorderConfigFromQR() error = %v", err)
+	}
+	roundTrip, err := CrossBorder(*got)
+	if err != nil {
+		t.Fatalf("CrossBorder(CrossBorderConfigFromQR()) error = %v", err)
+	}
+	if roundTrip != payload {
+		t.Errorf("CrossBorder(CrossBorderConfigFromQR()) = %v, want %v", roundTrip, payload)
+	}
+}
+
+func TestCrossBorder_Errors(t *testing.T) {
+	_, err := CrossBorder(CrossBorderConfig{AcquirerID: "999", MerchantID: "12345", MCC: "0000", TerminalID: "t-1"})
+
+	var errs ConfigErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("CrossBorder() error = %v, want ConfigErrors", err)
+	}
+	want := []string{"AcquirerID", "MerchantID", "TerminalID", "MCC", "Name", "City"}
+	if len(errs) != len(want) {
+		t.Fatalf("CrossBorder() error = %v, want %d errors", err, len(want))
+	}
+	for i, field := range want {
+		if errs[i].Field != field {
+			t.Errorf("errors[%d].Field = %v, want %v", i, errs[i].Field, field)
+		}
+	}
+}
+
+func TestCrossBorderConfigFromQR_WrongType(t *testing.T) {
+	payload, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: "0812345678"})
+	if err != nil {
+		t.Fatalf("AnyID() error = %v", err)
+	}
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := CrossBorderConfigFromQR(qr); err == nil {
+		t.Error("CrossBorderConfigFromQR() with AnyID QR should return error")
+	}
+}
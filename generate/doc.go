@@ -1,10 +1,13 @@
 // Package generate provides functions for generating Thai QR code payloads.
 //
 // Supported QR code types:
+//   - Generic EMVCo merchant QR codes (Merchant builder)
 //   - PromptPay AnyID (Tag 29)
 //   - PromptPay Bill Payment (Tag 30)
 //   - TrueMoney QR codes
 //   - Slip Verify QR codes
 //   - TrueMoney Slip Verify QR codes
 //   - BOT Barcode strings
+//
+// Sign adds an HMAC signature to a payload so that issuers can detect tampered QR codes.
 package generate
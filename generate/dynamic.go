@@ -0,0 +1,130 @@
+package generate
+
+import (
+	"time"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+// maxReferenceLabelLength is the maximum length of the reference label (Tag 62.05).
+const maxReferenceLabelLength = 25
+
+// DynamicConfig configures a dynamic, single-use merchant QR code.
+type DynamicConfig struct {
+	// Merchant holds the merchant account templates, amount and other data.
+	// It is not modified.
+	Merchant *Merchant
+
+	// Reference is the order or transaction reference (Tag 62.05, up to 25 characters)
+	Reference string
+
+	// ExpiresAt is the expiry time (optional if TTL is set)
+	ExpiresAt time.Time
+
+	// TTL is how long the QR code is valid after IssuedAt, used if ExpiresAt is zero
+	TTL time.Duration
+
+	// IssuedAt is the issue time (default: time.Now())
+	IssuedAt time.Time
+
+	// Signature signs the payee, amount and reference (optional, see Sign)
+	Signature *SignatureConfig
+}
+
+// DynamicQR is a dynamic QR code together with the metadata to store for
+// matching incoming payments to orders.
+type DynamicQR struct {
+	// Payload is the QR code payload
+	Payload string
+
+	// Reference is the order or transaction reference (Tag 62.05)
+	Reference string
+
+	// Amount is the transaction amount, if any
+	Amount *thaiqrgo.Amount
+
+	// IssuedAt is the issue time
+	IssuedAt time.Time
+
+	// ExpiresAt is the expiry time as encoded in the payload (UTC, whole seconds)
+	ExpiresAt time.Time
+}
+
+// Dynamic generates a dynamic, single-use QR code (Tag 01 is "12").
+//
+// The reference is stored as the reference label (Tag 62.05) and the expiry time
+// in a payment system specific template (Tag 62.50) identified by thaiqrgo.ExpiryGUID.
+// Scanned codes report them with EMVCoQR.ReferenceLabel, EMVCoQR.ExpiresAt and EMVCoQR.Expired.
+// If config.Signature is set, the payload is signed with Sign.
+func Dynamic(config DynamicConfig) (*DynamicQR, error) {
+	var check configCheck
+	if config.Merchant == nil {
+		check.fail("Merchant", "(none)", "required")
+	}
+	if check.required("Reference", config.Reference) {
+		check.text("Reference", config.Reference, maxReferenceLabelLength, charsetPrintable, isPrintable)
+	}
+
+	issuedAt := config.IssuedAt
+	if issuedAt.IsZero() {
+		issuedAt = time.Now()
+	}
+	expiresAt := config.ExpiresAt
+	switch {
+	case !expiresAt.IsZero():
+		if !expiresAt.After(issuedAt) {
+			check.fail("ExpiresAt", expiresAt.Format(time.RFC3339), "after IssuedAt")
+		}
+	case config.TTL > 0:
+		expiresAt = issuedAt.Add(config.TTL)
+	case config.TTL < 0:
+		check.fail("TTL", config.TTL.String(), "positive")
+	default:
+		check.fail("ExpiresAt", "(none)", "required unless TTL is set")
+	}
+	expiresAt = expiresAt.UTC().Truncate(time.Second)
+
+	if config.Merchant != nil {
+		for _, tag := range config.Merchant.AdditionalData {
+			if tag.ID == "05" || tag.ID == "50" {
+				check.fail("Merchant.AdditionalData", tag.ID, "no Tag 62."+tag.ID)
+			}
+		}
+	}
+	if err := check.err(); err != nil {
+		return nil, err
+	}
+
+	merchant := *config.Merchant
+	merchant.PointOfInitiation = "12"
+	merchant.AdditionalData = append(append([]thaiqrgo.TLVTag(nil), merchant.AdditionalData...),
+		thaiqrgo.Tag("05", config.Reference),
+		thaiqrgo.Tag("50", thaiqrgo.Encode([]thaiqrgo.TLVTag{
+			thaiqrgo.Tag("00", thaiqrgo.ExpiryGUID),
+			thaiqrgo.Tag("01", expiresAt.Format(thaiqrgo.ExpiryLayout)),
+		})),
+	)
+
+	payload, err := merchant.Build()
+	if err != nil {
+		return nil, err
+	}
+	if config.Signature != nil {
+		payload, err = Sign(payload, *config.Signature)
+		if errs, ok := err.(ConfigErrors); ok {
+			for _, e := range errs {
+				e.Field = "Signature." + e.Field
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &DynamicQR{
+		Payload:   payload,
+		Reference: config.Reference,
+		Amount:    merchant.Amount,
+		IssuedAt:  issuedAt,
+		ExpiresAt: expiresAt,
+	}, nil
+}
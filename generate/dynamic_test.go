@@ -0,0 +1,89 @@
+package generate
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+func testMerchant() *Merchant {
+	amount := thaiqrgo.MustParseAmount("250.00")
+	merchant := &Merchant{Amount: &amount, PointOfInitiation: "11"}
+	merchant.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeMSISDN, "0066812345678"))
+	return merchant
+}
+
+func TestDynamic(t *testing.T) {
+	merchant := testMerchant()
+	issuedAt := time.Date(2025, time.March, 1, 17, 0, 0, 500, time.FixedZone("ICT", 7*60*60))
+
+	qr, err := Dynamic(DynamicConfig{
+		Merchant:  merchant,
+		Reference: "ORDER-1001",
+		TTL:       15 * time.Minute,
+		IssuedAt:  issuedAt,
+	})
+	if err != nil {
+		t.Fatalf("Dynamic() error = %v", err)
+	}
+
+	wantExpiry := time.Date(2025, time.March, 1, 10, 15, 0, 0, time.UTC)
+	if !qr.ExpiresAt.Equal(wantExpiry) {
+		t.Errorf("Dynamic().ExpiresAt = %v, want %v", qr.ExpiresAt, wantExpiry)
+	}
+	if qr.Reference != "ORDER-1001" || qr.Amount.String() != "250.00" || !qr.IssuedAt.Equal(issuedAt) {
+		t.Errorf("Dynamic() = %+v", qr)
+	}
+	if merchant.PointOfInitiation != "11" || merchant.AdditionalData != nil {
+		t.Error("Dynamic() modified the merchant")
+	}
+
+	parsed, err := thaiqrgo.Parse(qr.Payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !parsed.IsDynamic() {
+		t.Error("IsDynamic() = false, want true")
+	}
+	if got := parsed.ReferenceLabel(); got != "ORDER-1001" {
+		t.Errorf("ReferenceLabel() = %v, want %v", got, "ORDER-1001")
+	}
+	if got, ok := parsed.ExpiresAt(); !ok || !got.Equal(wantExpiry) {
+		t.Errorf("ExpiresAt() = %v, %v, want %v", got, ok, wantExpiry)
+	}
+	if parsed.Expired(issuedAt) || !parsed.Expired(wantExpiry) {
+		t.Error("Expired() does not match the expiry time")
+	}
+}
+
+func TestDynamic_Errors(t *testing.T) {
+	issuedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	withReference := testMerchant()
+	withReference.AdditionalData = []thaiqrgo.TLVTag{thaiqrgo.Tag("05", "X")}
+
+	tests := []struct {
+		name   string
+		config DynamicConfig
+		field  string
+	}{
+		{"no merchant", DynamicConfig{Reference: "A", TTL: time.Minute}, "Merchant"},
+		{"no reference", DynamicConfig{Merchant: testMerchant(), TTL: time.Minute}, "Reference"},
+		{"long reference", DynamicConfig{Merchant: testMerchant(), Reference: "ORDER-00000000000000000001", TTL: time.Minute}, "Reference"},
+		{"no expiry", DynamicConfig{Merchant: testMerchant(), Reference: "A"}, "ExpiresAt"},
+		{"expired", DynamicConfig{Merchant: testMerchant(), Reference: "A", IssuedAt: issuedAt, ExpiresAt: issuedAt}, "ExpiresAt"},
+		{"negative TTL", DynamicConfig{Merchant: testMerchant(), Reference: "A", TTL: -time.Minute}, "TTL"},
+		{"reference label taken", DynamicConfig{Merchant: withReference, Reference: "A", TTL: time.Minute}, "Merchant.AdditionalData"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Dynamic(tt.config)
+			var configErr *InvalidConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("Dynamic() error = %v, want %s error", err, tt.field)
+			}
+		})
+	}
+}
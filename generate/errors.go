@@ -0,0 +1,158 @@
+package generate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/thaiid"
+)
+
+// InvalidConfigError represents an error in configuration.
+type InvalidConfigError struct {
+	// Field is the configuration field, e.g. "Ref1" or "Accounts[0].ID"
+	Field string
+
+	// Value is the rejected value
+	Value string
+
+	// Rule names the broken rule (e.g. "required" or "max length 20")
+	Rule string
+}
+
+func (e *InvalidConfigError) Error() string {
+	if e.Rule == "" {
+		return "invalid config: " + e.Field + " = " + e.Value
+	}
+	return "invalid config: " + e.Field + " = " + e.Value + " breaks rule: " + e.Rule
+}
+
+// ConfigErrors lists every invalid field of a configuration.
+//
+// All generators return it when validation fails. errors.As with an
+// *InvalidConfigError target finds the first invalid field.
+type ConfigErrors []*InvalidConfigError
+
+func (e ConfigErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	var b strings.Builder
+	b.WriteString("invalid config: " + strconv.Itoa(len(e)) + " errors")
+	for i, err := range e {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		b.WriteString(strings.TrimPrefix(err.Error(), "invalid config: "))
+	}
+	return b.String()
+}
+
+// Unwrap returns the individual errors.
+func (e ConfigErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// configCheck collects the invalid fields of a configuration.
+type configCheck struct {
+	errs ConfigErrors
+}
+
+// fail records a broken rule.
+func (c *configCheck) fail(field, value, rule string) {
+	c.errs = append(c.errs, &InvalidConfigError{Field: field, Value: value, Rule: rule})
+}
+
+// err returns the collected errors, or nil if there are none.
+func (c *configCheck) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+// required records an empty value and reports whether the value is present.
+func (c *configCheck) required(field, value string) bool {
+	if value == "" {
+		c.fail(field, value, "required")
+		return false
+	}
+	return true
+}
+
+// text checks the length and characters of a text field.
+func (c *configCheck) text(field, value string, maxLength int, charset string, valid func(byte) bool) {
+	if len(value) > maxLength {
+		c.fail(field, value, "max length "+strconv.Itoa(maxLength))
+	}
+	for i := 0; i < len(value); i++ {
+		if !valid(value[i]) {
+			c.fail(field, value, charset)
+			return
+		}
+	}
+}
+
+// amount checks that an amount can be shown in baht and satang.
+func (c *configCheck) amount(field string, amount *thaiqrgo.Amount) {
+	if amount != nil && amount.MinorUnits() > thaiqrgo.THBMinorUnits {
+		c.fail(field, amount.String(), "at most 2 decimal places")
+	}
+}
+
+// payloadSize checks that an encoded payload fits in a QR code.
+func (c *configCheck) payloadSize(payload string) {
+	if len(payload) > maxPayloadSize {
+		c.fail("Payload", strconv.Itoa(len(payload))+" bytes", "max length "+strconv.Itoa(maxPayloadSize))
+	}
+}
+
+// billerID checks a biller ID and reports whether it is valid.
+func (c *configCheck) billerID(billerID string) bool {
+	if !c.required("BillerID", billerID) {
+		return false
+	}
+	err := thaiid.ValidateBillerID(billerID)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, thaiid.ErrDigits):
+		c.fail("BillerID", billerID, "numeric")
+	case errors.Is(err, thaiid.ErrChecksum):
+		c.fail("BillerID", billerID, "mod 11 check digit")
+	default:
+		c.fail("BillerID", billerID, "13 or 15 digits")
+	}
+	return false
+}
+
+// Character classes of text fields.
+const (
+	charsetNumeric      = "numeric"
+	charsetAlphanumeric = "alphanumeric"
+	charsetUpper        = "uppercase alphanumeric"
+	charsetPrintable    = "printable ASCII"
+)
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlphanumericByte(c byte) bool {
+	return isDigit(c) || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isUpperAlphanumeric(c byte) bool {
+	return isDigit(c) || (c >= 'A' && c <= 'Z')
+}
+
+func isPrintable(c byte) bool {
+	return c >= 0x20 && c <= 0x7e
+}
@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+func TestConfigErrors(t *testing.T) {
+	longRef := strings.Repeat("1", 30)
+	ref2 := "A\tB"
+	wholeBaht, _ := thaiqrgo.NewAmount(100, 0)
+	fine, _ := thaiqrgo.NewAmount(12345, 3)
+
+	type rule struct{ field, rule string }
+	tests := []struct {
+		name     string
+		generate func() (string, error)
+		want     []rule
+	}{
+		{
+			"AnyID",
+			func() (string, error) { return AnyID(AnyIDConfig{Type: "EMAIL", Amount: &fine}) },
+			[]rule{{"Type", "one of MSISDN, NATID, EWALLETID, BANKACC"}, {"Amount", "at most 2 decimal places"}},
+		},
+		{
+			"BillPayment",
+			func() (string, error) {
+				return BillPayment(BillPaymentConfig{Ref1: longRef, Ref2: &ref2})
+			},
+			[]rule{{"BillerID", "required"}, {"Ref1", "max length 20"}, {"Ref2", "printable ASCII"}},
+		},
+		{
+			"BillPayment missing Ref1",
+			func() (string, error) { return BillPayment(BillPaymentConfig{BillerID: "0994000165501X0"}) },
+			[]rule{{"BillerID", "numeric"}, {"Ref1", "required"}},
+		},
+		{
+			"BOTBarcode",
+			func() (string, error) {
+				return BOTBarcode(BOTBarcodeConfig{BillerID: "099400016550200", Ref1: "inv-1", Amount: &wholeBaht})
+			},
+			[]rule{{"BillerID", "mod 11 check digit"}, {"Ref1", "uppercase alphanumeric"}, {"Amount", "amount in satang (2 decimal places)"}},
+		},
+		{
+			"TrueMoney",
+			func() (string, error) { return TrueMoney(TrueMoneyConfig{MobileNo: "021234567"}) },
+			[]rule{{"MobileNo", "Thai mobile number"}},
+		},
+		{
+			"SlipVerify",
+			func() (string, error) { return SlipVerify(SlipVerifyConfig{SendingBank: "KBK"}) },
+			[]rule{{"SendingBank", "3 digits"}, {"TransRef", "required"}},
+		},
+		{
+			"SlipVerify long TransRef",
+			func() (string, error) {
+				return SlipVerify(SlipVerifyConfig{SendingBank: "004", TransRef: strings.Repeat("A", 79)})
+			},
+			[]rule{{"TransRef", "max length 78"}},
+		},
+		{
+			"TrueMoneySlipVerify",
+			func() (string, error) {
+				return TrueMoneySlipVerify(TrueMoneySlipVerifyConfig{EventType: "p2p", TransactionID: "50000000000000", Date: "31022025"})
+			},
+			[]rule{{"EventType", "uppercase alphanumeric"}, {"Date", "DDMMYYYY date"}},
+		},
+		{
+			"TrueMoneySlipVerify empty",
+			func() (string, error) { return TrueMoneySlipVerify(TrueMoneySlipVerifyConfig{}) },
+			[]rule{{"EventType", "required"}, {"TransactionID", "required"}, {"Date", "required"}},
+		},
+		{
+			"Merchant",
+			func() (string, error) {
+				m := &Merchant{MCC: "58A2", Country: "th"}
+				return m.Build()
+			},
+			[]rule{{"Accounts", "at least one account"}, {"MCC", "4 digits"}, {"Country", "2 uppercase letters"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.generate()
+			if err == nil {
+				t.Fatalf("payload = %v, want error", got)
+			}
+			var errs ConfigErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("error = %v, want ConfigErrors", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("error = %v, want %d errors", err, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if errs[i].Field != want.field || errs[i].Rule != want.rule {
+					t.Errorf("errors[%d] = %s (%s), want %s (%s)", i, errs[i].Field, errs[i].Rule, want.field, want.rule)
+				}
+			}
+
+			var configErr *InvalidConfigError
+			if !errors.As(err, &configErr) || configErr != errs[0] {
+				t.Errorf("errors.As() = %v, want first error %v", configErr, errs[0])
+			}
+		})
+	}
+}
+
+func TestConfigErrors_Error(t *testing.T) {
+	errs := ConfigErrors{
+		{Field: "BillerID", Value: "", Rule: "required"},
+		{Field: "Ref1", Value: "ABC", Rule: "numeric"},
+	}
+	want := "invalid config: 2 errors: BillerID =  breaks rule: required; Ref1 = ABC breaks rule: numeric"
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got, want := errs[:1].Error(), "invalid config: BillerID =  breaks rule: required"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestTrueMoneySlipVerify_ValidDate(t *testing.T) {
+	_, err := TrueMoneySlipVerify(TrueMoneySlipVerifyConfig{EventType: "P2P", TransactionID: "50000000000000", Date: "29022024"})
+	if err != nil {
+		t.Errorf("TrueMoneySlipVerify() error = %v", err)
+	}
+}
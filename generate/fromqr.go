@@ -0,0 +1,224 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/bank"
+	"github.com/klimakov/thai-qr-go/ewallet"
+	"github.com/klimakov/thai-qr-go/phone"
+)
+
+// AnyIDConfigFromQR extracts an AnyIDConfig from a parsed PromptPay AnyID QR code.
+//
+// The QR code must be parsed with sub-tags. Passing the result to AnyID
+// reproduces the original payload.
+func AnyIDConfigFromQR(qr *thaiqrgo.EMVCoQR) (*AnyIDConfig, error) {
+	if qr.GetTagValue("29", "00") != aidAnyID {
+		return nil, errors.New("invalid AnyID QR: missing PromptPay AnyID template (Tag 29)")
+	}
+
+	config := &AnyIDConfig{}
+	switch {
+	case qr.GetTag("29", ProxyTypeMSISDN) != nil:
+		number, err := phone.FromProxy(qr.GetTagValue("29", ProxyTypeMSISDN))
+		if err != nil {
+			return nil, fmt.Errorf("invalid AnyID QR: %w", err)
+		}
+		config.Type = "MSISDN"
+		config.Target = number.Local()
+	case qr.GetTag("29", ProxyTypeNATID) != nil:
+		config.Type = "NATID"
+		config.Target = qr.GetTagValue("29", ProxyTypeNATID)
+	case qr.GetTag("29", ProxyTypeEWALLETID) != nil:
+		id := qr.GetTagValue("29", ProxyTypeEWALLETID)
+		if err := ewallet.ValidateFormat(id); err != nil {
+			return nil, fmt.Errorf("invalid AnyID QR: %w", err)
+		}
+		config.Type = "EWALLETID"
+		config.Target = id
+		if wallet, err := ewallet.Parse(id); err == nil {
+			config.WalletCode = wallet.Provider.Code
+			config.Target = wallet.Account
+		}
+	case qr.GetTag("29", ProxyTypeBANKACC) != nil:
+		account, err := bank.ParseProxy(qr.GetTagValue("29", ProxyTypeBANKACC))
+		if err != nil {
+			return nil, fmt.Errorf("invalid AnyID QR: %w", err)
+		}
+		config.Type = "BANKACC"
+		config.BankCode = account.Bank.Code
+		config.Target = account.Number
+	default:
+		return nil, errors.New("invalid AnyID QR: missing proxy in Tag 29")
+	}
+
+	amount, err := amountFromQR(qr)
+	if err != nil {
+		return nil, err
+	}
+	config.Amount = amount
+
+	return config, nil
+}
+
+// BillPaymentConfigFromQR extracts a BillPaymentConfig from a parsed PromptPay Bill Payment QR code.
+//
+// The QR code must be parsed with sub-tags. Passing the result to BillPayment
+// reproduces the original payload.
+func BillPaymentConfigFromQR(qr *thaiqrgo.EMVCoQR) (*BillPaymentConfig, error) {
+	if qr.GetTagValue("30", "00") != aidBillPayment {
+		return nil, errors.New("invalid Bill Payment QR: missing PromptPay Bill Payment template (Tag 30)")
+	}
+
+	billerID := qr.GetTag("30", "01")
+	ref1 := qr.GetTag("30", "02")
+	if billerID == nil || ref1 == nil {
+		return nil, errors.New("invalid Bill Payment QR: missing required fields")
+	}
+
+	config := &BillPaymentConfig{
+		BillerID: billerID.Value,
+		Ref1:     ref1.Value,
+	}
+
+	if ref2 := qr.GetTag("30", "03"); ref2 != nil {
+		value := ref2.Value
+		config.Ref2 = &value
+	}
+
+	if ref3 := qr.GetTag("62", "07"); ref3 != nil {
+		value := ref3.Value
+		config.Ref3 = &value
+	}
+
+	amount, err := amountFromQR(qr)
+	if err != nil {
+		return nil, err
+	}
+	config.Amount = amount
+
+	return config, nil
+}
+
+// CrossBorderConfigFromQR extracts a CrossBorderConfig from a parsed PromptPay
+// cross-border merchant QR code.
+//
+// The QR code must be parsed with sub-tags. Passing the result to CrossBorder
+// reproduces the original payload.
+func CrossBorderConfigFromQR(qr *thaiqrgo.EMVCoQR) (*CrossBorderConfig, error) {
+	merchant, ok := qr.CrossBorderMerchant()
+	if !ok {
+		return nil, errors.New("invalid cross-border QR: missing PromptPay cross-border template (Tag 30)")
+	}
+
+	config := &CrossBorderConfig{
+		AcquirerID: merchant.AcquirerID,
+		MerchantID: merchant.MerchantID,
+		TerminalID: merchant.TerminalID,
+		MCC:        merchant.MCC,
+		Name:       merchant.Name,
+		City:       merchant.City,
+		PostalCode: qr.GetTagValue("61", ""),
+	}
+
+	amount, err := amountFromQR(qr)
+	if err != nil {
+		return nil, err
+	}
+	config.Amount = amount
+
+	return config, nil
+}
+
+// TrueMoneyConfigFromQR extracts a TrueMoneyConfig from a parsed TrueMoney QR code.
+//
+// The QR code must be parsed with sub-tags. The personal message (Tag 81) is decoded
+// back to text. Passing the result to TrueMoney reproduces the original payload.
+func TrueMoneyConfigFromQR(qr *thaiqrgo.EMVCoQR) (*TrueMoneyConfig, error) {
+	if qr.GetTagValue("29", "00") != aidAnyID {
+		return nil, errors.New("invalid TrueMoney QR: missing PromptPay AnyID template (Tag 29)")
+	}
+
+	wallet, err := ewallet.Parse(qr.GetTagValue("29", ProxyTypeEWALLETID))
+	if err != nil || wallet.Provider.Code != ewallet.CodeTrueMoney {
+		return nil, errors.New("invalid TrueMoney QR: missing TrueMoney e-wallet ID")
+	}
+
+	config := &TrueMoneyConfig{
+		MobileNo: wallet.Account,
+	}
+
+	amount, err := amountFromQR(qr)
+	if err != nil {
+		return nil, err
+	}
+	config.Amount = amount
+
+	if qr.GetTag("81", "") != nil {
+		message, err := qr.PersonalMessage()
+		if err != nil {
+			return nil, fmt.Errorf("invalid TrueMoney QR: %w", err)
+		}
+		config.Message = &message
+	}
+
+	return config, nil
+}
+
+// SlipVerifyConfigFromQR extracts a SlipVerifyConfig from a parsed Slip Verify QR code.
+//
+// The QR code must be parsed with sub-tags. Passing the result to SlipVerify
+// reproduces the original payload.
+func SlipVerifyConfigFromQR(qr *thaiqrgo.EMVCoQR) (*SlipVerifyConfig, error) {
+	if qr.GetTagValue("00", "00") != "000001" {
+		return nil, errors.New("invalid Slip Verify QR: incorrect API type")
+	}
+
+	config := &SlipVerifyConfig{
+		SendingBank: qr.GetTagValue("00", "01"),
+		TransRef:    qr.GetTagValue("00", "02"),
+	}
+	if config.SendingBank == "" || config.TransRef == "" {
+		return nil, errors.New("invalid Slip Verify QR: missing required fields")
+	}
+
+	return config, nil
+}
+
+// TrueMoneySlipVerifyConfigFromQR extracts a TrueMoneySlipVerifyConfig from a parsed
+// TrueMoney Slip Verify QR code.
+//
+// The QR code must be parsed with sub-tags. Passing the result to TrueMoneySlipVerify
+// reproduces the original payload.
+func TrueMoneySlipVerifyConfigFromQR(qr *thaiqrgo.EMVCoQR) (*TrueMoneySlipVerifyConfig, error) {
+	if qr.GetTagValue("00", "00") != "01" || qr.GetTagValue("00", "01") != "01" {
+		return nil, errors.New("invalid TrueMoney Slip Verify QR: incorrect API type")
+	}
+
+	config := &TrueMoneySlipVerifyConfig{
+		EventType:     qr.GetTagValue("00", "02"),
+		TransactionID: qr.GetTagValue("00", "03"),
+		Date:          qr.GetTagValue("00", "04"),
+	}
+	if config.EventType == "" || config.TransactionID == "" || config.Date == "" {
+		return nil, errors.New("invalid TrueMoney Slip Verify QR: missing required fields")
+	}
+
+	return config, nil
+}
+
+// amountFromQR reads the transaction amount (Tag 54), if present.
+func amountFromQR(qr *thaiqrgo.EMVCoQR) (*thaiqrgo.Amount, error) {
+	tag54 := qr.GetTag("54", "")
+	if tag54 == nil {
+		return nil, nil
+	}
+
+	amount, err := thaiqrgo.ParseAmount(tag54.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
+}
@@ -0,0 +1,160 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+func mustParse(t *testing.T, payload string) *thaiqrgo.EMVCoQR {
+	t.Helper()
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", payload, err)
+	}
+	return qr
+}
+
+func TestAnyIDConfigFromQR_RoundTrip(t *testing.T) {
+	amount := thaiqrgo.MustParseAmount("30")
+	configs := []AnyIDConfig{
+		{Type: "MSISDN", Target: "0812223333"},
+		{Type: "MSISDN", Target: "0812223333", Amount: &amount},
+		{Type: "NATID", Target: "1111111111119"},
+		{Type: "EWALLETID", WalletCode: "140", Target: "0812223333"},
+		{Type: "BANKACC", BankCode: "004", Target: "1234567890"},
+	}
+
+	for _, config := range configs {
+		t.Run(config.Type, func(t *testing.T) {
+			payload, err := AnyID(config)
+			if err != nil {
+				t.Fatalf("AnyID() error = %v", err)
+			}
+			got, err := AnyIDConfigFromQR(mustParse(t, payload))
+			if err != nil {
+				t.Fatalf("AnyIDConfigFromQR() error = %v", err)
+			}
+			if got.Type != config.Type {
+				t.Errorf("AnyIDConfigFromQR() Type = %v, want %v", got.Type, config.Type)
+			}
+			if got.Target != config.Target {
+				t.Errorf("AnyIDConfigFromQR() Target = %v, want %v", got.Target, config.Target)
+			}
+			if got.WalletCode != config.WalletCode {
+				t.Errorf("AnyIDConfigFromQR() WalletCode = %v, want %v", got.WalletCode, config.WalletCode)
+			}
+			if got.BankCode != config.BankCode {
+				t.Errorf("AnyIDConfigFromQR() BankCode = %v, want %v", got.BankCode, config.BankCode)
+			}
+			regenerated, err := AnyID(*got)
+			if err != nil {
+				t.Fatalf("AnyID() error = %v", err)
+			}
+			if regenerated != payload {
+				t.Errorf("AnyID(AnyIDConfigFromQR()) = %v, want %v", regenerated, payload)
+			}
+		})
+	}
+}
+
+func TestBillPaymentConfigFromQR_RoundTrip(t *testing.T) {
+	payloads := []string{
+		"00020101021130620016A000000677010112011301122334455610211CUSTOMER0010306INV00153037645802TH62070703SCB63049D58",
+		"00020101021230650016A00000067701011201150994000165501000212123456789012030667042953037645802TH54073649.2263044534",
+		"00020101021130550016A0000006770101120115099999999999190021211122233344453037645802TH630464CE",
+	}
+
+	for _, payload := range payloads {
+		config, err := BillPaymentConfigFromQR(mustParse(t, payload))
+		if err != nil {
+			t.Fatalf("BillPaymentConfigFromQR() error = %v", err)
+		}
+		got, err := BillPayment(*config)
+		if err != nil {
+			t.Fatalf("BillPayment() error = %v", err)
+		}
+		if got != payload {
+			t.Errorf("BillPayment(BillPaymentConfigFromQR()) = %v, want %v", got, payload)
+		}
+	}
+}
+
+func TestTrueMoneyConfigFromQR_RoundTrip(t *testing.T) {
+	payload := "00020101021229390016A000000677010111031514000080111111153037645802TH540510.05814800480065006C006C006F00200057006F0072006C006400216304F5A2"
+	config, err := TrueMoneyConfigFromQR(mustParse(t, payload))
+	if err != nil {
+		t.Fatalf("TrueMoneyConfigFromQR() error = %v", err)
+	}
+	if config.MobileNo != "0801111111" {
+		t.Errorf("TrueMoneyConfigFromQR() MobileNo = %v, want 0801111111", config.MobileNo)
+	}
+	if config.Message == nil || *config.Message != "Hello World!" {
+		t.Errorf("TrueMoneyConfigFromQR() Message = %v, want Hello World!", config.Message)
+	}
+	got, err := TrueMoney(*config)
+	if err != nil {
+		t.Fatalf("TrueMoney() error = %v", err)
+	}
+	if got != payload {
+		t.Errorf("TrueMoney(TrueMoneyConfigFromQR()) = %v, want %v", got, payload)
+	}
+}
+
+func TestSlipVerifyConfigFromQR_RoundTrip(t *testing.T) {
+	payload := "004000060000010103002021900021231231212000115102TH91049C30"
+	config, err := SlipVerifyConfigFromQR(mustParse(t, payload))
+	if err != nil {
+		t.Fatalf("SlipVerifyConfigFromQR() error = %v", err)
+	}
+	got, err := SlipVerify(*config)
+	if err != nil {
+		t.Fatalf("SlipVerify() error = %v", err)
+	}
+	if got != payload {
+		t.Errorf("SlipVerify(SlipVerifyConfigFromQR()) = %v, want %v", got, payload)
+	}
+}
+
+func TestTrueMoneySlipVerifyConfigFromQR_RoundTrip(t *testing.T) {
+	payload, err := TrueMoneySlipVerify(TrueMoneySlipVerifyConfig{
+		EventType:     "P2P",
+		TransactionID: "TXN123456",
+		Date:          "08122024",
+	})
+	if err != nil {
+		t.Fatalf("TrueMoneySlipVerify() error = %v", err)
+	}
+	config, err := TrueMoneySlipVerifyConfigFromQR(mustParse(t, payload))
+	if err != nil {
+		t.Fatalf("TrueMoneySlipVerifyConfigFromQR() error = %v", err)
+	}
+	got, err := TrueMoneySlipVerify(*config)
+	if err != nil {
+		t.Fatalf("TrueMoneySlipVerify() error = %v", err)
+	}
+	if got != payload {
+		t.Errorf("TrueMoneySlipVerify(TrueMoneySlipVerifyConfigFromQR()) = %v, want %v", got, payload)
+	}
+}
+
+func TestConfigFromQR_WrongType(t *testing.T) {
+	slip := mustParse(t, "004000060000010103002021900021231231212000115102TH91049C30")
+	anyID := mustParse(t, "00020101021129370016A0000006770101110113006681222333353037645802TH63041DCF")
+
+	if _, err := AnyIDConfigFromQR(slip); err == nil {
+		t.Error("AnyIDConfigFromQR() should return error for Slip Verify QR")
+	}
+	if _, err := BillPaymentConfigFromQR(anyID); err == nil {
+		t.Error("BillPaymentConfigFromQR() should return error for AnyID QR")
+	}
+	if _, err := TrueMoneyConfigFromQR(anyID); err == nil {
+		t.Error("TrueMoneyConfigFromQR() should return error for non-TrueMoney QR")
+	}
+	if _, err := SlipVerifyConfigFromQR(anyID); err == nil {
+		t.Error("SlipVerifyConfigFromQR() should return error for AnyID QR")
+	}
+	if _, err := TrueMoneySlipVerifyConfigFromQR(slip); err == nil {
+		t.Error("TrueMoneySlipVerifyConfigFromQR() should return error for Slip Verify QR")
+	}
+}
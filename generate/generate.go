@@ -1,11 +1,17 @@
 package generate
 
 import (
+	"errors"
 	"strconv"
-	"strings"
+	"time"
 
 	"github.com/klimakov/thai-qr-go"
-	"github.com/klimakov/thai-qr-go/internal"
+	"github.com/klimakov/thai-qr-go/aid"
+	"github.com/klimakov/thai-qr-go/bank"
+	"github.com/klimakov/thai-qr-go/biller"
+	"github.com/klimakov/thai-qr-go/ewallet"
+	"github.com/klimakov/thai-qr-go/phone"
+	"github.com/klimakov/thai-qr-go/thaiid"
 )
 
 // ProxyType constants for PromptPay AnyID.
@@ -16,71 +22,135 @@ const (
 	ProxyTypeBANKACC   = "04" // Bank Account (Reserved)
 )
 
+// Field length limits.
+const (
+	maxTemplateLength   = 99 // value of a TLV template
+	maxRefLength        = 20 // Tag 30 references
+	maxRef3Length       = 25 // Tag 62.07
+	maxBarcodeRefLength = 18 // BOT Barcode references
+)
+
+// Application identifiers of the PromptPay merchant account templates.
+const (
+	aidAnyID       = aid.PromptPayAnyID
+	aidBillPayment = aid.PromptPayBillPayment
+)
+
 // AnyIDConfig configures a PromptPay AnyID QR code.
 type AnyIDConfig struct {
 	// Type is the proxy type (MSISDN, NATID, EWALLETID, BANKACC)
 	Type string
 
-	// Target is the recipient number
+	// Target is the recipient number (e.g. "0812345678" or "+66 81 234 5678" for MSISDN).
+	// For BANKACC it is the account number, or the bank code followed by the
+	// account number if BankCode is empty.
 	Target string
 
+	// BankCode is the 3-digit bank code for BANKACC (optional, see package bank)
+	BankCode string
+
+	// WalletCode is the 3-digit e-wallet provider code for EWALLETID
+	// (optional, see package ewallet). If set, Target is the wallet account and
+	// is checked against the registered provider, otherwise it is the full
+	// 15-digit e-wallet ID of any provider.
+	WalletCode string
+
 	// Amount is the transaction amount (optional)
-	Amount *float64
+	Amount *thaiqrgo.Amount
 }
 
 // AnyID generates a PromptPay AnyID (Tag 29) QR code payload.
+//
+// MSISDN targets may be given in local, E.164 or formatted form (see package phone).
+// NATID targets must be valid 13-digit national IDs or tax IDs (see package thaiid).
+// EWALLETID targets must be IDs of a registered e-wallet provider (see package ewallet).
+// BANKACC targets must match the account number rules of a registered bank (see package bank).
 func AnyID(config AnyIDConfig) (string, error) {
+	var check configCheck
 	target := config.Target
 
-	if config.Type == "MSISDN" {
-		// Remove leading 0 and prepend country code 66, pad to 13 digits
-		target = strings.TrimPrefix(target, "0")
-		target = "66" + target
-		// Pad to 13 digits from the right
-		for len(target) < 13 {
-			target = "0" + target
-		}
-		if len(target) > 13 {
-			target = target[len(target)-13:]
-		}
-	}
-
 	var proxyTypeValue string
 	switch config.Type {
 	case "MSISDN":
 		proxyTypeValue = ProxyTypeMSISDN
+		if number, err := phone.Parse(target); err != nil {
+			check.fail("Target", target, "Thai mobile number")
+		} else {
+			target = number.Proxy()
+		}
 	case "NATID":
 		proxyTypeValue = ProxyTypeNATID
+		if !thaiid.Valid(target) {
+			check.fail("Target", target, "13-digit ID with mod 11 check digit")
+		}
 	case "EWALLETID":
 		proxyTypeValue = ProxyTypeEWALLETID
+		target = eWalletID(&check, config.WalletCode, target)
 	case "BANKACC":
 		proxyTypeValue = ProxyTypeBANKACC
+		target = bankAccountProxy(&check, config.BankCode, target)
+	case "":
+		check.fail("Type", config.Type, "required")
 	default:
-		return "", &InvalidConfigError{Field: "Type", Value: config.Type}
+		check.fail("Type", config.Type, "one of MSISDN, NATID, EWALLETID, BANKACC")
+	}
+	check.amount("Amount", config.Amount)
+	if err := check.err(); err != nil {
+		return "", err
 	}
 
-	tag29 := thaiqrgo.Encode([]thaiqrgo.TLVTag{
-		thaiqrgo.Tag("00", "A000000677010111"),
-		thaiqrgo.Tag(proxyTypeValue, target),
-	})
+	merchant := &Merchant{Amount: config.Amount}
+	merchant.AddAccount("29", aidAnyID, thaiqrgo.Tag(proxyTypeValue, target))
+	return merchant.Build()
+}
 
-	var payload []thaiqrgo.TLVTag
-	payload = append(payload, thaiqrgo.Tag("00", "01"))
-	if config.Amount != nil {
-		payload = append(payload, thaiqrgo.Tag("01", "12"))
-	} else {
-		payload = append(payload, thaiqrgo.Tag("01", "11"))
+// eWalletID checks an e-wallet account and returns its 15-digit ID.
+//
+// Without a provider code any well-formed ID is accepted, so that wallets of
+// providers missing from the registry keep working.
+func eWalletID(check *configCheck, code, target string) string {
+	if code == "" {
+		if err := ewallet.ValidateFormat(target); err != nil {
+			check.fail("Target", target, "15-digit e-wallet ID")
+		}
+		return target
 	}
-	payload = append(payload, thaiqrgo.Tag("29", tag29))
-	payload = append(payload, thaiqrgo.Tag("53", "764"))
-	payload = append(payload, thaiqrgo.Tag("58", "TH"))
 
-	if config.Amount != nil {
-		amountStr := strconv.FormatFloat(*config.Amount, 'f', 2, 64)
-		payload = append(payload, thaiqrgo.Tag("54", amountStr))
+	provider, ok := ewallet.Lookup(code)
+	if !ok {
+		check.fail("WalletCode", code, "registered e-wallet provider")
+		return target
+	}
+	id, err := provider.ID(target)
+	if err != nil {
+		check.fail("Target", target, provider.NameEN+" account")
+		return target
 	}
+	return id
+}
 
-	return thaiqrgo.WithCRCTag(thaiqrgo.Encode(payload), "63", true), nil
+// bankAccountProxy checks a bank account and returns its BANKACC proxy value.
+func bankAccountProxy(check *configCheck, code, target string) string {
+	if code == "" {
+		target = bank.Clean(target)
+		if len(target) < 3 {
+			check.fail("Target", target, "bank code followed by account number")
+			return target
+		}
+		code, target = target[:3], target[3:]
+	}
+
+	b, ok := bank.Lookup(code)
+	if !ok {
+		check.fail("BankCode", code, "registered bank code")
+		return target
+	}
+	account, err := bank.NewAccount(code, target)
+	if err != nil {
+		check.fail("Target", target, b.ShortName+" account number")
+		return target
+	}
+	return account.Proxy()
 }
 
 // BillPaymentConfig configures a PromptPay Bill Payment QR code.
@@ -89,7 +159,7 @@ type BillPaymentConfig struct {
 	BillerID string
 
 	// Amount is the transaction amount (optional)
-	Amount *float64
+	Amount *thaiqrgo.Amount
 
 	// Ref1 is reference 1
 	Ref1 string
@@ -99,55 +169,65 @@ type BillPaymentConfig struct {
 
 	// Ref3 is reference 3 (optional, undocumented)
 	Ref3 *string
+
+	// Directory is the biller directory to validate against (default: biller.Default)
+	Directory *biller.Directory
 }
 
 // BillPayment generates a PromptPay Bill Payment (Tag 30) QR code payload.
+//
+// The biller ID must start with a valid 13-digit tax ID (see package thaiid).
+// Ref1 is required; Ref1 and Ref2 are printable ASCII of up to 20 characters.
+// The references and amount must also follow the biller's rules if the biller
+// is in the directory (see package biller).
 func BillPayment(config BillPaymentConfig) (string, error) {
-	tag30 := []thaiqrgo.TLVTag{
-		thaiqrgo.Tag("00", "A000000677010112"),
-		thaiqrgo.Tag("01", config.BillerID),
-		thaiqrgo.Tag("02", config.Ref1),
+	var check configCheck
+	validBiller := check.billerID(config.BillerID)
+	if check.required("Ref1", config.Ref1) {
+		check.text("Ref1", config.Ref1, maxRefLength, charsetPrintable, isPrintable)
 	}
-
 	if config.Ref2 != nil {
-		tag30 = append(tag30, thaiqrgo.Tag("03", *config.Ref2))
+		check.text("Ref2", *config.Ref2, maxRefLength, charsetPrintable, isPrintable)
 	}
-
-	var payload []thaiqrgo.TLVTag
-	payload = append(payload, thaiqrgo.Tag("00", "01"))
-	if config.Amount != nil {
-		payload = append(payload, thaiqrgo.Tag("01", "12"))
-	} else {
-		payload = append(payload, thaiqrgo.Tag("01", "11"))
+	if config.Ref3 != nil {
+		check.text("Ref3", *config.Ref3, maxRef3Length, charsetPrintable, isPrintable)
+	}
+	check.amount("Amount", config.Amount)
+	if validBiller {
+		checkDirectory(&check, config.Directory, config.BillerID, config.Ref1, config.Ref2, config.Amount)
+	}
+	if err := check.err(); err != nil {
+		return "", err
 	}
-	payload = append(payload, thaiqrgo.Tag("30", thaiqrgo.Encode(tag30)))
-	payload = append(payload, thaiqrgo.Tag("53", "764"))
-	payload = append(payload, thaiqrgo.Tag("58", "TH"))
 
-	if config.Amount != nil {
-		amountStr := strconv.FormatFloat(*config.Amount, 'f', 2, 64)
-		payload = append(payload, thaiqrgo.Tag("54", amountStr))
+	fields := []thaiqrgo.TLVTag{
+		thaiqrgo.Tag("01", config.BillerID),
+		thaiqrgo.Tag("02", config.Ref1),
 	}
+	if config.Ref2 != nil {
+		fields = append(fields, thaiqrgo.Tag("03", *config.Ref2))
+	}
+
+	merchant := &Merchant{Amount: config.Amount}
+	merchant.AddAccount("30", aidBillPayment, fields...)
 
 	if config.Ref3 != nil {
-		tag62 := thaiqrgo.Encode([]thaiqrgo.TLVTag{
-			thaiqrgo.Tag("07", *config.Ref3),
-		})
-		payload = append(payload, thaiqrgo.Tag("62", tag62))
+		merchant.AdditionalData = []thaiqrgo.TLVTag{thaiqrgo.Tag("07", *config.Ref3)}
 	}
 
-	return thaiqrgo.WithCRCTag(thaiqrgo.Encode(payload), "63", true), nil
+	return merchant.Build()
 }
 
 // TrueMoneyConfig configures a TrueMoney QR code.
 type TrueMoneyConfig struct {
-	// MobileNo is the mobile number
+	// MobileNo is the mobile number (local, E.164 or formatted, see package phone)
 	MobileNo string
 
 	// Amount is the transaction amount (optional)
-	Amount *float64
+	Amount *thaiqrgo.Amount
 
-	// Message is a personal message for Tag 81 (optional)
+	// Message is a personal message for Tag 81 (optional,
+	// up to thaiqrgo.MaxPersonalMessageLength UTF-16 code units)
 	Message *string
 }
 
@@ -156,38 +236,36 @@ type TrueMoneyConfig struct {
 // This QR code can also be scanned with other apps, just like a regular e-Wallet PromptPay QR,
 // but the Personal Message (Tag 81) will be ignored.
 func TrueMoney(config TrueMoneyConfig) (string, error) {
-	tag29 := thaiqrgo.Encode([]thaiqrgo.TLVTag{
-		thaiqrgo.Tag("00", "A000000677010111"),
-		thaiqrgo.Tag("03", "14000"+config.MobileNo),
-	})
-
-	var payload []thaiqrgo.TLVTag
-	payload = append(payload, thaiqrgo.Tag("00", "01"))
-	if config.Amount != nil {
-		payload = append(payload, thaiqrgo.Tag("01", "12"))
-	} else {
-		payload = append(payload, thaiqrgo.Tag("01", "11"))
+	var check configCheck
+	id, err := ewallet.TrueMoney.ID(config.MobileNo)
+	if err != nil {
+		check.fail("MobileNo", config.MobileNo, "Thai mobile number")
 	}
-	payload = append(payload, thaiqrgo.Tag("29", tag29))
-	payload = append(payload, thaiqrgo.Tag("53", "764"))
-	payload = append(payload, thaiqrgo.Tag("58", "TH"))
-
-	if config.Amount != nil {
-		amountStr := strconv.FormatFloat(*config.Amount, 'f', 2, 64)
-		payload = append(payload, thaiqrgo.Tag("54", amountStr))
+	check.amount("Amount", config.Amount)
+	var message string
+	if config.Message != nil {
+		message, err = thaiqrgo.EncodePersonalMessage(*config.Message)
+		if err != nil {
+			check.fail("Message", *config.Message, "max "+strconv.Itoa(thaiqrgo.MaxPersonalMessageLength)+" UTF-16 code units")
+		}
+	}
+	if err := check.err(); err != nil {
+		return "", err
 	}
 
+	merchant := &Merchant{Amount: config.Amount}
+	merchant.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeEWALLETID, id))
+
 	if config.Message != nil {
-		encodedMsg := internal.EncodeTag81(*config.Message)
-		payload = append(payload, thaiqrgo.Tag("81", encodedMsg))
+		merchant.Unreserved = []thaiqrgo.TLVTag{thaiqrgo.Tag("81", message)}
 	}
 
-	return thaiqrgo.WithCRCTag(thaiqrgo.Encode(payload), "63", true), nil
+	return merchant.Build()
 }
 
 // SlipVerifyConfig configures a Slip Verify QR code.
 type SlipVerifyConfig struct {
-	// SendingBank is the bank code
+	// SendingBank is the 3-digit bank code (e.g. "004")
 	SendingBank string
 
 	// TransRef is the transaction reference
@@ -197,12 +275,30 @@ type SlipVerifyConfig struct {
 // SlipVerify generates a Slip Verify QR code.
 //
 // This is also called "Mini-QR" that is embedded in slips used for verifying transactions.
+// The sending bank must be in the bank registry (see package bank) and the
+// transaction reference must be alphanumeric.
 func SlipVerify(config SlipVerifyConfig) (string, error) {
+	var check configCheck
+	if check.required("SendingBank", config.SendingBank) {
+		if len(config.SendingBank) != 3 || !isNumeric(config.SendingBank) {
+			check.fail("SendingBank", config.SendingBank, "3 digits")
+		} else if _, ok := bank.Lookup(config.SendingBank); !ok {
+			check.fail("SendingBank", config.SendingBank, "registered bank code")
+		}
+	}
+	if check.required("TransRef", config.TransRef) {
+		check.text("TransRef", config.TransRef, maxTemplateLength, charsetAlphanumeric, isAlphanumericByte)
+	}
+
 	tag00 := thaiqrgo.Encode([]thaiqrgo.TLVTag{
 		thaiqrgo.Tag("00", "000001"),
 		thaiqrgo.Tag("01", config.SendingBank),
 		thaiqrgo.Tag("02", config.TransRef),
 	})
+	checkTemplate(&check, "TransRef", config.TransRef, tag00)
+	if err := check.err(); err != nil {
+		return "", err
+	}
 
 	payload := []thaiqrgo.TLVTag{
 		thaiqrgo.Tag("00", tag00),
@@ -220,7 +316,7 @@ type TrueMoneySlipVerifyConfig struct {
 	// TransactionID is the transaction ID
 	TransactionID string
 
-	// Date is the date in DDMMYYYY format
+	// Date is the date in DDMMYYYY format (e.g. "31012025")
 	Date string
 }
 
@@ -232,6 +328,19 @@ type TrueMoneySlipVerifyConfig struct {
 //   - Additional tags that are TrueMoney-specific
 //   - CRC checksum is case-sensitive (lowercase)
 func TrueMoneySlipVerify(config TrueMoneySlipVerifyConfig) (string, error) {
+	var check configCheck
+	if check.required("EventType", config.EventType) {
+		check.text("EventType", config.EventType, maxTemplateLength, charsetUpper, isUpperAlphanumeric)
+	}
+	if check.required("TransactionID", config.TransactionID) {
+		check.text("TransactionID", config.TransactionID, maxTemplateLength, charsetAlphanumeric, isAlphanumericByte)
+	}
+	if check.required("Date", config.Date) {
+		if _, err := time.Parse("02012006", config.Date); err != nil || len(config.Date) != 8 {
+			check.fail("Date", config.Date, "DDMMYYYY date")
+		}
+	}
+
 	tag00 := thaiqrgo.Encode([]thaiqrgo.TLVTag{
 		thaiqrgo.Tag("00", "01"),
 		thaiqrgo.Tag("01", "01"),
@@ -239,6 +348,10 @@ func TrueMoneySlipVerify(config TrueMoneySlipVerifyConfig) (string, error) {
 		thaiqrgo.Tag("03", config.TransactionID),
 		thaiqrgo.Tag("04", config.Date),
 	})
+	checkTemplate(&check, "TransactionID", config.TransactionID, tag00)
+	if err := check.err(); err != nil {
+		return "", err
+	}
 
 	payload := []thaiqrgo.TLVTag{
 		thaiqrgo.Tag("00", tag00),
@@ -259,25 +372,52 @@ type BOTBarcodeConfig struct {
 	Ref2 *string
 
 	// Amount is the transaction amount (optional)
-	Amount *float64
+	Amount *thaiqrgo.Amount
+
+	// Directory is the biller directory to validate against (default: biller.Default)
+	Directory *biller.Directory
 }
 
 // BOTBarcode generates a BOT Barcode string.
-func BOTBarcode(config BOTBarcodeConfig) string {
+//
+// The biller ID must start with a valid 13-digit tax ID (see package thaiid).
+// Ref1 is required; Ref1 and Ref2 are digits and uppercase letters, up to 18 characters.
+// The references and amount must also follow the biller's rules if the biller
+// is in the directory (see package biller).
+func BOTBarcode(config BOTBarcodeConfig) (string, error) {
+	var check configCheck
+	validBiller := check.billerID(config.BillerID)
+	if check.required("Ref1", config.Ref1) {
+		check.text("Ref1", config.Ref1, maxBarcodeRefLength, charsetUpper, isUpperAlphanumeric)
+	}
+	if config.Ref2 != nil {
+		check.text("Ref2", *config.Ref2, maxBarcodeRefLength, charsetUpper, isUpperAlphanumeric)
+	}
+	if config.Amount != nil && config.Amount.MinorUnits() != thaiqrgo.THBMinorUnits {
+		check.fail("Amount", config.Amount.String(), "amount in satang (2 decimal places)")
+	}
+	if validBiller {
+		checkDirectory(&check, config.Directory, config.BillerID, config.Ref1, config.Ref2, config.Amount)
+	}
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
 	barcode := &thaiqrgo.BOTBarcode{
 		BillerID: config.BillerID,
 		Ref1:     config.Ref1,
 		Ref2:     config.Ref2,
 		Amount:   config.Amount,
 	}
-	return barcode.String()
+	return barcode.String(), nil
 }
 
 // BOTBarcodeToQR converts a BOT Barcode to a PromptPay QR Tag 30 (Bill Payment) payload.
 //
 // This function works for some billers, depending on the destination bank.
 // It takes the same parameters as BOTBarcode and returns a QR code payload.
-func BOTBarcodeToQR(billerID, ref1 string, ref2 *string, amount *float64) (string, error) {
+// The data is validated against biller.Default.
+func BOTBarcodeToQR(billerID, ref1 string, ref2 *string, amount *thaiqrgo.Amount) (string, error) {
 	config := BillPaymentConfig{
 		BillerID: billerID,
 		Ref1:     ref1,
@@ -287,12 +427,22 @@ func BOTBarcodeToQR(billerID, ref1 string, ref2 *string, amount *float64) (strin
 	return BillPayment(config)
 }
 
-// InvalidConfigError represents an error in configuration.
-type InvalidConfigError struct {
-	Field string
-	Value string
+// checkDirectory checks bill payment data against the rules of a biller directory.
+func checkDirectory(check *configCheck, directory *biller.Directory, billerID, ref1 string, ref2 *string, amount *thaiqrgo.Amount) {
+	if directory == nil {
+		directory = biller.Default
+	}
+	var ruleErr *biller.RuleError
+	if err := directory.Validate(billerID, ref1, ref2, amount != nil); errors.As(err, &ruleErr) {
+		check.fail(ruleErr.Field, ruleErr.Value, ruleErr.Rule)
+	}
 }
 
-func (e *InvalidConfigError) Error() string {
-	return "invalid config: " + e.Field + " = " + e.Value
+// checkTemplate checks that an encoded template fits in a single TLV value,
+// blaming the field of variable length.
+func checkTemplate(check *configCheck, field, value, template string) {
+	if len(template) > maxTemplateLength {
+		limit := len(value) - (len(template) - maxTemplateLength)
+		check.fail(field, value, "max length "+strconv.Itoa(max(limit, 0)))
+	}
 }
@@ -1,11 +1,14 @@
 package generate
 
 import (
+	"errors"
 	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/biller"
 )
 
 func TestAnyID(t *testing.T) {
-	amount := 0.0
 	config := AnyIDConfig{
 		Type:   "MSISDN",
 		Target: "0812223333",
@@ -21,7 +24,7 @@ func TestAnyID(t *testing.T) {
 	}
 
 	// Test with amount
-	amount = 30.0
+	amount := thaiqrgo.MustParseAmount("30")
 	config.Amount = &amount
 	got, err = AnyID(config)
 	if err != nil {
@@ -64,7 +67,7 @@ func TestTrueMoney(t *testing.T) {
 	}
 
 	// Test with amount and message
-	amount := 10.05
+	amount := thaiqrgo.MustParseAmount("10.05")
 	message := "Hello World!"
 	config.Amount = &amount
 	config.Message = &message
@@ -82,7 +85,7 @@ func TestBillPayment(t *testing.T) {
 	ref2 := "INV001"
 	ref3 := "SCB"
 	config := BillPaymentConfig{
-		BillerID: "0112233445566",
+		BillerID: "0112233445561",
 		Ref1:     "CUSTOMER001",
 		Ref2:     &ref2,
 		Ref3:     &ref3,
@@ -92,7 +95,7 @@ func TestBillPayment(t *testing.T) {
 	if err != nil {
 		t.Fatalf("BillPayment() error = %v", err)
 	}
-	want := "00020101021130620016A000000677010112011301122334455660211CUSTOMER0010306INV00153037645802TH62070703SCB6304780E"
+	want := "00020101021130620016A000000677010112011301122334455610211CUSTOMER0010306INV00153037645802TH62070703SCB63049D58"
 	if got != want {
 		t.Errorf("BillPayment() = %v, want %v", got, want)
 	}
@@ -100,25 +103,31 @@ func TestBillPayment(t *testing.T) {
 
 func TestBOTBarcode(t *testing.T) {
 	config := BOTBarcodeConfig{
-		BillerID: "099999999999990",
+		BillerID: "099999999999190",
 		Ref1:     "111222333444",
 		Ref2:     nil,
 		Amount:   nil,
 	}
-	got := BOTBarcode(config)
-	want := "|099999999999990\r111222333444\r\r0"
+	got, err := BOTBarcode(config)
+	if err != nil {
+		t.Fatalf("BOTBarcode() error = %v", err)
+	}
+	want := "|099999999999190\r111222333444\r\r0"
 	if got != want {
 		t.Errorf("BOTBarcode() = %v, want %v", got, want)
 	}
 
 	// Test with Ref2 and amount
 	ref2 := "670429"
-	amount := 3649.22
+	amount := thaiqrgo.MustParseAmount("3649.22")
 	config.BillerID = "099400016550100"
 	config.Ref1 = "123456789012"
 	config.Ref2 = &ref2
 	config.Amount = &amount
-	got = BOTBarcode(config)
+	got, err = BOTBarcode(config)
+	if err != nil {
+		t.Fatalf("BOTBarcode() error = %v", err)
+	}
 	want = "|099400016550100\r123456789012\r670429\r364922"
 	if got != want {
 		t.Errorf("BOTBarcode() with ref2 and amount = %v, want %v", got, want)
@@ -158,7 +167,7 @@ func TestAnyID_EdgeCases(t *testing.T) {
 
 	// Test with NATID type
 	config.Type = "NATID"
-	config.Target = "1111111111111"
+	config.Target = "1111111111119"
 	got, err := AnyID(config)
 	if err != nil {
 		t.Fatalf("AnyID() with NATID error = %v", err)
@@ -169,7 +178,7 @@ func TestAnyID_EdgeCases(t *testing.T) {
 
 	// Test with EWALLETID type
 	config.Type = "EWALLETID"
-	config.Target = "012345678901234"
+	config.Target = "140000812223333"
 	got, err = AnyID(config)
 	if err != nil {
 		t.Fatalf("AnyID() with EWALLETID error = %v", err)
@@ -180,6 +189,7 @@ func TestAnyID_EdgeCases(t *testing.T) {
 
 	// Test with BANKACC type
 	config.Type = "BANKACC"
+	config.Target = "0041234567890"
 	got, err = AnyID(config)
 	if err != nil {
 		t.Fatalf("AnyID() with BANKACC error = %v", err)
@@ -191,9 +201,9 @@ func TestAnyID_EdgeCases(t *testing.T) {
 
 func TestBillPayment_EdgeCases(t *testing.T) {
 	// Test with amount
-	amount := 100.50
+	amount := thaiqrgo.MustParseAmount("100.50")
 	config := BillPaymentConfig{
-		BillerID: "0112233445566",
+		BillerID: "0112233445561",
 		Ref1:     "CUSTOMER001",
 		Ref2:     nil,
 		Ref3:     nil,
@@ -222,7 +232,7 @@ func TestBillPayment_EdgeCases(t *testing.T) {
 
 func TestBOTBarcodeToQR(t *testing.T) {
 	ref2 := "670429"
-	amount := 3649.22
+	amount := thaiqrgo.MustParseAmount("3649.22")
 	got, err := BOTBarcodeToQR("099400016550100", "123456789012", &ref2, &amount)
 	if err != nil {
 		t.Fatalf("BOTBarcodeToQR() error = %v", err)
@@ -233,12 +243,237 @@ func TestBOTBarcodeToQR(t *testing.T) {
 	}
 
 	// Test without ref2 and amount
-	got, err = BOTBarcodeToQR("099999999999990", "111222333444", nil, nil)
+	got, err = BOTBarcodeToQR("099999999999190", "111222333444", nil, nil)
 	if err != nil {
 		t.Fatalf("BOTBarcodeToQR() without ref2/amount error = %v", err)
 	}
-	want = "00020101021130550016A0000006770101120115099999999999990021211122233344453037645802TH63043EE7"
+	want = "00020101021130550016A0000006770101120115099999999999190021211122233344453037645802TH630464CE"
 	if got != want {
 		t.Errorf("BOTBarcodeToQR() without ref2/amount = %v, want %v", got, want)
 	}
 }
+
+func TestTaxIDChecksum(t *testing.T) {
+	var configErr *InvalidConfigError
+
+	_, err := AnyID(AnyIDConfig{Type: "NATID", Target: "1111111111111"})
+	if !errors.As(err, &configErr) || configErr.Field != "Target" {
+		t.Errorf("AnyID() with invalid NATID error = %v, want Target error", err)
+	}
+
+	_, err = BillPayment(BillPaymentConfig{BillerID: "099999999999990", Ref1: "1"})
+	if !errors.As(err, &configErr) || configErr.Field != "BillerID" {
+		t.Errorf("BillPayment() with invalid biller ID error = %v, want BillerID error", err)
+	}
+
+	_, err = BOTBarcode(BOTBarcodeConfig{BillerID: "09940001655010", Ref1: "1"})
+	if !errors.As(err, &configErr) || configErr.Field != "BillerID" {
+		t.Errorf("BOTBarcode() with invalid biller ID error = %v, want BillerID error", err)
+	}
+
+	if _, err := BOTBarcodeToQR("0994000165502", "1", nil, nil); err == nil {
+		t.Error("BOTBarcodeToQR() should return error for invalid biller ID")
+	}
+}
+
+func TestAnyID_MSISDNForms(t *testing.T) {
+	want, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: "0812223333"})
+	if err != nil {
+		t.Fatalf("AnyID() error = %v", err)
+	}
+
+	for _, target := range []string{"081-222-3333", "+66 81 222 3333", "66812223333", "0066812223333"} {
+		got, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: target})
+		if err != nil {
+			t.Errorf("AnyID(%q) error = %v", target, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("AnyID(%q) = %v, want %v", target, got, want)
+		}
+	}
+
+	for _, target := range []string{"08122233334", "0212223333", "+1 812 222 3333", "081222333"} {
+		if _, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: target}); err == nil {
+			t.Errorf("AnyID(%q) should return error", target)
+		}
+	}
+}
+
+func TestAnyID_BANKACC(t *testing.T) {
+	want := "00020101021129370016A0000006770101110413004123456789053037645802TH6304"
+	for _, config := range []AnyIDConfig{
+		{Type: "BANKACC", BankCode: "004", Target: "1234567890"},
+		{Type: "BANKACC", BankCode: "004", Target: "123-4-56789-0"},
+		{Type: "BANKACC", Target: "004-123-4-56789-0"},
+	} {
+		got, err := AnyID(config)
+		if err != nil {
+			t.Errorf("AnyID(%+v) error = %v", config, err)
+			continue
+		}
+		if got[:len(got)-4] != want {
+			t.Errorf("AnyID(%+v) = %v, want prefix %v", config, got, want)
+		}
+	}
+
+	var configErr *InvalidConfigError
+	tests := []struct {
+		config AnyIDConfig
+		field  string
+	}{
+		{AnyIDConfig{Type: "BANKACC", BankCode: "999", Target: "1234567890"}, "BankCode"},
+		{AnyIDConfig{Type: "BANKACC", BankCode: "004", Target: "123456789"}, "Target"},
+		{AnyIDConfig{Type: "BANKACC", BankCode: "030", Target: "1234567890"}, "Target"},
+		{AnyIDConfig{Type: "BANKACC", Target: "00"}, "Target"},
+	}
+	for _, tt := range tests {
+		_, err := AnyID(tt.config)
+		if !errors.As(err, &configErr) || configErr.Field != tt.field {
+			t.Errorf("AnyID(%+v) error = %v, want %s error", tt.config, err, tt.field)
+		}
+	}
+}
+
+func TestAnyID_EWALLETID(t *testing.T) {
+	want, err := TrueMoney(TrueMoneyConfig{MobileNo: "0801111111"})
+	if err != nil {
+		t.Fatalf("TrueMoney() error = %v", err)
+	}
+
+	for _, config := range []AnyIDConfig{
+		{Type: "EWALLETID", Target: "140000801111111"},
+		{Type: "EWALLETID", WalletCode: "140", Target: "080-111-1111"},
+	} {
+		got, err := AnyID(config)
+		if err != nil {
+			t.Errorf("AnyID(%+v) error = %v", config, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("AnyID(%+v) = %v, want %v", config, got, want)
+		}
+	}
+
+	var configErr *InvalidConfigError
+	tests := []struct {
+		config AnyIDConfig
+		field  string
+	}{
+		{AnyIDConfig{Type: "EWALLETID", Target: "01234567890123"}, "Target"},
+		{AnyIDConfig{Type: "EWALLETID", Target: "01234567890123X"}, "Target"},
+		{AnyIDConfig{Type: "EWALLETID", WalletCode: "999", Target: "0801111111"}, "WalletCode"},
+		{AnyIDConfig{Type: "EWALLETID", WalletCode: "140", Target: "080111111"}, "Target"},
+	}
+	for _, tt := range tests {
+		_, err := AnyID(tt.config)
+		if !errors.As(err, &configErr) || configErr.Field != tt.field {
+			t.Errorf("AnyID(%+v) error = %v, want %s error", tt.config, err, tt.field)
+		}
+	}
+
+	// Wallets of providers missing from the registry
+	payload, err := AnyID(AnyIDConfig{Type: "EWALLETID", Target: "012345678901234"})
+	if err != nil {
+		t.Fatalf("AnyID() with unregistered provider error = %v", err)
+	}
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := qr.GetTagValue("29", ProxyTypeEWALLETID); got != "012345678901234" {
+		t.Errorf("AnyID() Tag 29.03 = %v, want 012345678901234", got)
+	}
+	if len(qr.Diagnostics()) != 0 {
+		t.Errorf("Parse() diagnostics = %v, want none", qr.Diagnostics())
+	}
+	config, err := AnyIDConfigFromQR(qr)
+	if err != nil || config.Target != "012345678901234" || config.WalletCode != "" {
+		t.Errorf("AnyIDConfigFromQR() = %+v, %v", config, err)
+	}
+
+	if _, err := TrueMoney(TrueMoneyConfig{MobileNo: "12345"}); !errors.As(err, &configErr) || configErr.Field != "MobileNo" {
+		t.Errorf("TrueMoney() with invalid mobile error = %v, want MobileNo error", err)
+	}
+}
+
+func TestSlipVerify_UnknownBank(t *testing.T) {
+	var configErr *InvalidConfigError
+	_, err := SlipVerify(SlipVerifyConfig{SendingBank: "999", TransRef: "0002123123121200011"})
+	if !errors.As(err, &configErr) || configErr.Field != "SendingBank" {
+		t.Errorf("SlipVerify() with unknown bank error = %v, want SendingBank error", err)
+	}
+}
+
+func TestBillPayment_Directory(t *testing.T) {
+	directory, err := biller.NewDirectory(biller.Biller{
+		TaxID:          "0994000165501",
+		Suffix:         "00",
+		Name:           "Test Utility",
+		Ref1:           biller.Rule{Required: true, MaxLength: 12, Charset: biller.CharsetNumeric},
+		AmountRequired: true,
+	})
+	if err != nil {
+		t.Fatalf("NewDirectory() error = %v", err)
+	}
+
+	amount := thaiqrgo.MustParseAmount("100")
+	config := BillPaymentConfig{BillerID: "099400016550100", Ref1: "123456789012", Amount: &amount, Directory: directory}
+	if _, err := BillPayment(config); err != nil {
+		t.Fatalf("BillPayment() error = %v", err)
+	}
+
+	var configErr *InvalidConfigError
+	config.Ref1 = "ABC"
+	if _, err := BillPayment(config); !errors.As(err, &configErr) || configErr.Field != "Ref1" {
+		t.Errorf("BillPayment() with invalid ref1 error = %v, want Ref1 error", err)
+	}
+
+	config.Ref1 = "123456789012"
+	config.Amount = nil
+	if _, err := BillPayment(config); !errors.As(err, &configErr) || configErr.Field != "Amount" {
+		t.Errorf("BillPayment() without amount error = %v, want Amount error", err)
+	}
+
+	_, err = BOTBarcode(BOTBarcodeConfig{BillerID: "099400016550100", Ref1: "123456789012", Directory: directory})
+	if !errors.As(err, &configErr) || configErr.Field != "Amount" {
+		t.Errorf("BOTBarcode() without amount error = %v, want Amount error", err)
+	}
+
+	// Billers missing from a non-strict directory are not checked
+	config.BillerID = "099999999999190"
+	if _, err := BillPayment(config); err != nil {
+		t.Errorf("BillPayment() for unknown biller error = %v", err)
+	}
+
+	directory.Strict = true
+	if _, err := BillPayment(config); !errors.As(err, &configErr) || configErr.Field != "BillerID" {
+		t.Errorf("BillPayment() for unknown biller in strict directory error = %v, want BillerID error", err)
+	}
+}
+
+func TestTrueMoney_Message(t *testing.T) {
+	message := "ขอบคุณ 🙏"
+	payload, err := TrueMoney(TrueMoneyConfig{MobileNo: "0801111111", Message: &message})
+	if err != nil {
+		t.Fatalf("TrueMoney() error = %v", err)
+	}
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, err := qr.PersonalMessage(); err != nil || got != message {
+		t.Errorf("PersonalMessage() = %q, %v, want %q", got, err, message)
+	}
+	config, err := TrueMoneyConfigFromQR(qr)
+	if err != nil || config.Message == nil || *config.Message != message {
+		t.Errorf("TrueMoneyConfigFromQR() Message = %v, %v, want %q", config.Message, err, message)
+	}
+
+	long := "This message is far too long for Tag 81"
+	var configErr *InvalidConfigError
+	_, err = TrueMoney(TrueMoneyConfig{MobileNo: "0801111111", Message: &long})
+	if !errors.As(err, &configErr) || configErr.Field != "Message" {
+		t.Errorf("TrueMoney() with long message error = %v, want Message error", err)
+	}
+}
@@ -0,0 +1,250 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+// maxPayloadSize is the maximum size of an EMVCo QR code payload.
+const maxPayloadSize = 512
+
+// MerchantAccount is a merchant account information field (Tags 02-51).
+//
+// Tags 02-25 are primitive fields reserved for card schemes and hold Value;
+// tags 26-51 are templates that hold GUID and Fields.
+type MerchantAccount struct {
+	// ID is the tag ID (02-51), e.g. "29" for PromptPay AnyID or "02" for Visa
+	ID string
+
+	// GUID is the globally unique identifier (AID) stored in sub-tag 00 of a template
+	GUID string
+
+	// Fields are the template sub-tags that follow the GUID
+	Fields []thaiqrgo.TLVTag
+
+	// Value is the merchant ID of a primitive card scheme field (Tags 02-25)
+	Value string
+}
+
+// Merchant builds an EMVCo merchant-presented QR code payload.
+//
+// It composes any set of merchant account templates with the root data objects.
+// The fixed recipes in this package (AnyID, BillPayment, TrueMoney) are presets over it.
+type Merchant struct {
+	// Accounts are the merchant account information templates (at least one)
+	Accounts []MerchantAccount
+
+	// PointOfInitiation is "11" (static) or "12" (dynamic).
+	// If empty, it is "12" when Amount is set and "11" otherwise.
+	PointOfInitiation string
+
+	// MCC is the merchant category code (Tag 52, optional)
+	MCC string
+
+	// Currency is the ISO 4217 numeric currency code (Tag 53, default: "764")
+	Currency string
+
+	// Country is the ISO 3166-1 alpha-2 country code (Tag 58, default: "TH")
+	Country string
+
+	// Amount is the transaction amount (Tag 54, optional)
+	Amount *thaiqrgo.Amount
+
+	// Name is the merchant name (Tag 59, optional)
+	Name string
+
+	// City is the merchant city (Tag 60, optional)
+	City string
+
+	// PostalCode is the merchant postal code (Tag 61, optional)
+	PostalCode string
+
+	// AdditionalData are the sub-tags of the additional data field template (Tag 62, optional)
+	AdditionalData []thaiqrgo.TLVTag
+
+	// LanguageTemplate are the sub-tags of the merchant information language template (Tag 64, optional)
+	LanguageTemplate []thaiqrgo.TLVTag
+
+	// Unreserved are root tags 80-99, e.g. the TrueMoney personal message (Tag 81)
+	Unreserved []thaiqrgo.TLVTag
+}
+
+// AddAccount appends a merchant account template and returns the builder.
+func (m *Merchant) AddAccount(id, guid string, fields ...thaiqrgo.TLVTag) *Merchant {
+	m.Accounts = append(m.Accounts, MerchantAccount{ID: id, GUID: guid, Fields: fields})
+	return m
+}
+
+// Build validates the merchant data and returns the QR code payload with its CRC tag.
+//
+// Root tags are emitted in the order used by the PromptPay reference implementation:
+// 00, 01, merchant accounts, 52, 53, 58, 54, 59, 60, 61, 62, 64, 80-99 and 63.
+func (m *Merchant) Build() (string, error) {
+	if err := m.validate(); err != nil {
+		return "", err
+	}
+
+	initiation := m.PointOfInitiation
+	if initiation == "" {
+		initiation = "11"
+		if m.Amount != nil {
+			initiation = "12"
+		}
+	}
+
+	payload := []thaiqrgo.TLVTag{
+		thaiqrgo.Tag("00", "01"),
+		thaiqrgo.Tag("01", initiation),
+	}
+
+	for _, account := range m.Accounts {
+		payload = append(payload, thaiqrgo.Tag(account.ID, encodeAccount(account)))
+	}
+
+	if m.MCC != "" {
+		payload = append(payload, thaiqrgo.Tag("52", m.MCC))
+	}
+	payload = append(payload, thaiqrgo.Tag("53", valueOr(m.Currency, "764")))
+	payload = append(payload, thaiqrgo.Tag("58", valueOr(m.Country, "TH")))
+
+	if m.Amount != nil {
+		payload = append(payload, thaiqrgo.Tag("54", m.Amount.String()))
+	}
+
+	optional := []struct{ id, value string }{
+		{"59", m.Name},
+		{"60", m.City},
+		{"61", m.PostalCode},
+		{"62", thaiqrgo.Encode(m.AdditionalData)},
+		{"64", thaiqrgo.Encode(m.LanguageTemplate)},
+	}
+	for _, tag := range optional {
+		if tag.value != "" {
+			payload = append(payload, thaiqrgo.Tag(tag.id, tag.value))
+		}
+	}
+
+	payload = append(payload, m.Unreserved...)
+
+	result := thaiqrgo.WithCRCTag(thaiqrgo.Encode(payload), "63", true)
+	var check configCheck
+	check.payloadSize(result)
+	if err := check.err(); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// validate checks the merchant data against the EMVCo field rules.
+func (m *Merchant) validate() error {
+	var check configCheck
+	if len(m.Accounts) == 0 {
+		check.fail("Accounts", "(none)", "at least one account")
+	}
+
+	seen := make(map[string]bool, len(m.Accounts))
+	for i, account := range m.Accounts {
+		field := fmt.Sprintf("Accounts[%d]", i)
+		switch {
+		case !isNumeric(account.ID) || len(account.ID) != 2 || account.ID < "02" || account.ID > "51":
+			check.fail(field+".ID", account.ID, "template ID 02-51")
+		case seen[account.ID]:
+			check.fail(field+".ID", account.ID, "unique template ID")
+		}
+		seen[account.ID] = true
+		if account.ID >= "02" && account.ID <= "25" {
+			check.required(field+".Value", account.Value)
+			if account.GUID != "" || len(account.Fields) > 0 {
+				check.fail(field+".GUID", account.GUID, "none for card scheme tags 02-25")
+			}
+		} else {
+			check.required(field+".GUID", account.GUID)
+			if account.Value != "" {
+				check.fail(field+".Value", account.Value, "none for template tags 26-51")
+			}
+		}
+		if value := encodeAccount(account); len(value) > maxTemplateLength {
+			check.fail(field, value, "max length 99")
+		}
+	}
+
+	if m.PointOfInitiation != "" && m.PointOfInitiation != "11" && m.PointOfInitiation != "12" {
+		check.fail("PointOfInitiation", m.PointOfInitiation, "11 or 12")
+	}
+	if m.MCC != "" && (len(m.MCC) != 4 || !isNumeric(m.MCC)) {
+		check.fail("MCC", m.MCC, "4 digits")
+	}
+	if m.Currency != "" && (len(m.Currency) != 3 || !isNumeric(m.Currency)) {
+		check.fail("Currency", m.Currency, "3 digits")
+	}
+	if m.Country != "" && (len(m.Country) != 2 || !isUpperAlpha(m.Country)) {
+		check.fail("Country", m.Country, "2 uppercase letters")
+	}
+	if m.Currency == "" || m.Currency == "764" {
+		check.amount("Amount", m.Amount)
+	}
+
+	limits := []struct {
+		field, value string
+		max          int
+	}{
+		{"Name", m.Name, 25},
+		{"City", m.City, 15},
+		{"PostalCode", m.PostalCode, 10},
+		{"AdditionalData", thaiqrgo.Encode(m.AdditionalData), maxTemplateLength},
+		{"LanguageTemplate", thaiqrgo.Encode(m.LanguageTemplate), maxTemplateLength},
+	}
+	// Lengths count bytes, as written to the TLV length by thaiqrgo.Tag
+	for _, limit := range limits {
+		if len(limit.value) > limit.max {
+			check.fail(limit.field, limit.value, "max length "+strconv.Itoa(limit.max))
+		}
+	}
+
+	for i, tag := range m.Unreserved {
+		if len(tag.ID) != 2 || tag.ID < "80" || tag.ID > "99" || !isNumeric(tag.ID) {
+			check.fail(fmt.Sprintf("Unreserved[%d].ID", i), tag.ID, "tag ID 80-99")
+		}
+		if len(tag.Value) > maxTemplateLength {
+			check.fail(fmt.Sprintf("Unreserved[%d].Value", i), tag.Value, "max length 99")
+		}
+	}
+
+	return check.err()
+}
+
+// encodeAccount encodes a merchant account value.
+func encodeAccount(account MerchantAccount) string {
+	if account.GUID == "" && len(account.Fields) == 0 {
+		return account.Value
+	}
+	tags := append([]thaiqrgo.TLVTag{thaiqrgo.Tag("00", account.GUID)}, account.Fields...)
+	return thaiqrgo.Encode(tags)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isUpperAlpha(s string) bool {
+	for _, c := range s {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,141 @@
+package generate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+func TestMerchant_Build(t *testing.T) {
+	amount := thaiqrgo.MustParseAmount("50")
+	merchant := &Merchant{
+		MCC:        "5812",
+		Amount:     &amount,
+		Name:       "KLIMAKOV CAFE",
+		City:       "BANGKOK",
+		PostalCode: "10110",
+		AdditionalData: []thaiqrgo.TLVTag{
+			thaiqrgo.Tag("01", "INV001"),
+		},
+	}
+	merchant.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeNATID, "0994000165501"))
+
+	got, err := merchant.Build()
+	if err != nil {
+		t.Fatalf("Merchant.Build() error = %v", err)
+	}
+
+	qr, err := thaiqrgo.Parse(got, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := map[string]string{
+		"01": "12",
+		"52": "5812",
+		"53": "764",
+		"54": "50.00",
+		"58": "TH",
+		"59": "KLIMAKOV CAFE",
+		"60": "BANGKOK",
+		"61": "10110",
+	}
+	for id, value := range want {
+		if got := qr.GetTagValue(id, ""); got != value {
+			t.Errorf("Merchant.Build() Tag %s = %v, want %v", id, got, value)
+		}
+	}
+	if got := qr.GetTagValue("29", "02"); got != "0994000165501" {
+		t.Errorf("Merchant.Build() Tag 29.02 = %v, want 0994000165501", got)
+	}
+	if got := qr.GetTagValue("62", "01"); got != "INV001" {
+		t.Errorf("Merchant.Build() Tag 62.01 = %v, want INV001", got)
+	}
+}
+
+func TestMerchant_BuildMatchesPresets(t *testing.T) {
+	merchant := &Merchant{}
+	merchant.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeMSISDN, "0066812223333"))
+
+	got, err := merchant.Build()
+	if err != nil {
+		t.Fatalf("Merchant.Build() error = %v", err)
+	}
+	want, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: "0812223333"})
+	if err != nil {
+		t.Fatalf("AnyID() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Merchant.Build() = %v, want %v", got, want)
+	}
+}
+
+func TestMerchant_BuildMultibyteLanguageTemplate(t *testing.T) {
+	m := &Merchant{Name: "SHOP"}
+	m.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeMSISDN, "0066812223333"))
+	name := strings.Repeat("ก", 29) // 87 bytes, the longest that fits
+	m.LanguageTemplate = []thaiqrgo.TLVTag{thaiqrgo.Tag("00", "TH"), thaiqrgo.Tag("01", name)}
+
+	payload, err := m.Build()
+	if err != nil {
+		t.Fatalf("Merchant.Build() error = %v", err)
+	}
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got := qr.GetTagValue("64", "01"); got != name {
+		t.Errorf("Parse() Tag 64.01 = %v, want %v", got, name)
+	}
+}
+
+func TestMerchant_BuildInvalid(t *testing.T) {
+	valid := func() *Merchant {
+		m := &Merchant{}
+		return m.AddAccount("29", aidAnyID, thaiqrgo.Tag(ProxyTypeMSISDN, "0066812223333"))
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *Merchant)
+		field  string
+	}{
+		{"no accounts", func(m *Merchant) { m.Accounts = nil }, "Accounts"},
+		{"account ID out of range", func(m *Merchant) { m.Accounts[0].ID = "52" }, "Accounts[0].ID"},
+		{"duplicate account", func(m *Merchant) { m.AddAccount("29", aidAnyID) }, "Accounts[1].ID"},
+		{"missing GUID", func(m *Merchant) { m.Accounts[0].GUID = "" }, "Accounts[0].GUID"},
+		{"value in template", func(m *Merchant) { m.Accounts[0].Value = "X" }, "Accounts[0].Value"},
+		{"card scheme without value", func(m *Merchant) { m.Accounts = []MerchantAccount{{ID: "02"}} }, "Accounts[0].Value"},
+		{"card scheme with GUID", func(m *Merchant) { m.Accounts = []MerchantAccount{{ID: "02", GUID: aidAnyID, Value: "1"}} }, "Accounts[0].GUID"},
+		{"point of initiation", func(m *Merchant) { m.PointOfInitiation = "13" }, "PointOfInitiation"},
+		{"MCC", func(m *Merchant) { m.MCC = "58A2" }, "MCC"},
+		{"currency", func(m *Merchant) { m.Currency = "THB" }, "Currency"},
+		{"country", func(m *Merchant) { m.Country = "th" }, "Country"},
+		{"name too long", func(m *Merchant) { m.Name = "A VERY LONG MERCHANT NAME THAT DOES NOT FIT" }, "Name"},
+		{"city too long", func(m *Merchant) { m.City = "KRUNG THEP MAHA NAKHON" }, "City"},
+		{"multibyte language template", func(m *Merchant) {
+			m.LanguageTemplate = []thaiqrgo.TLVTag{thaiqrgo.Tag("00", "TH"), thaiqrgo.Tag("01", strings.Repeat("ก", 30))}
+		}, "LanguageTemplate"},
+		{"multibyte additional data", func(m *Merchant) {
+			m.AdditionalData = []thaiqrgo.TLVTag{thaiqrgo.Tag("08", strings.Repeat("ข", 32))}
+		}, "AdditionalData"},
+		{"unreserved tag ID", func(m *Merchant) { m.Unreserved = []thaiqrgo.TLVTag{thaiqrgo.Tag("70", "X")} }, "Unreserved[0].ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := valid()
+			tt.modify(m)
+			_, err := m.Build()
+			var configErr *InvalidConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Merchant.Build() error = %v, want *InvalidConfigError", err)
+			}
+			if configErr.Field != tt.field {
+				t.Errorf("Merchant.Build() error field = %v, want %v", configErr.Field, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/aid"
+)
+
+// SchemeAccount is a merchant account of a scheme in the AID registry (see package aid).
+type SchemeAccount struct {
+	// AID is the application identifier of a registered scheme, e.g. aid.PromptPayAnyID
+	AID string
+
+	// TagID is the merchant account tag (optional). By default it is the first
+	// free tag of the scheme, then, for template schemes, the first free tag 26-51.
+	TagID string
+
+	// MerchantID is the merchant ID of a card scheme (primitive Tags 02-25)
+	MerchantID string
+
+	// Fields are the sub-tags of a template scheme that follow the AID,
+	// e.g. thaiqrgo.Tag("01", "0066812345678") for a PromptPay MSISDN proxy
+	Fields []thaiqrgo.TLVTag
+}
+
+// MultiSchemeConfig configures a QR code with merchant accounts of several
+// payment schemes, so that one code can be paid from any supported app.
+type MultiSchemeConfig struct {
+	// Accounts are the merchant accounts, one per scheme (at least one)
+	Accounts []SchemeAccount
+
+	// Merchant holds the other merchant data, e.g. MCC, name, city and amount
+	// (optional). Its Accounts must be empty; it is not modified.
+	Merchant *Merchant
+}
+
+// MultiScheme generates a QR code payload that combines merchant accounts of
+// several schemes, e.g. PromptPay AnyID (Tag 29), PromptPay Bill Payment (Tag 30)
+// and Visa (Tag 02).
+//
+// Every AID must be in the AID registry, which also decides the tag of each
+// account. The combined payload must fit in 512 characters.
+func MultiScheme(config MultiSchemeConfig) (string, error) {
+	var check configCheck
+	if len(config.Accounts) == 0 {
+		check.fail("Accounts", "(none)", "at least one account")
+	}
+	if config.Merchant != nil && len(config.Merchant.Accounts) > 0 {
+		check.fail("Merchant.Accounts", strconv.Itoa(len(config.Merchant.Accounts)), "none (use Accounts)")
+	}
+
+	schemes := make([]aid.Scheme, len(config.Accounts))
+	used := make(map[string]bool)
+	for i, account := range config.Accounts {
+		field := fmt.Sprintf("Accounts[%d]", i)
+		s, ok := aid.Lookup(account.AID)
+		if !ok {
+			check.fail(field+".AID", account.AID, "registered AID")
+			continue
+		}
+		schemes[i] = s
+
+		if s.Primitive() {
+			check.required(field+".MerchantID", account.MerchantID)
+			if len(account.Fields) > 0 {
+				check.fail(field+".Fields", thaiqrgo.Encode(account.Fields), "none for card schemes")
+			}
+		} else if account.MerchantID != "" {
+			check.fail(field+".MerchantID", account.MerchantID, "none for template schemes")
+		}
+
+		if account.TagID == "" {
+			continue
+		}
+		switch {
+		case s.Primitive() && !slices.Contains(s.Tags, account.TagID):
+			check.fail(field+".TagID", account.TagID, "one of the "+s.Name+" tags")
+		case !s.Primitive() && (!isNumeric(account.TagID) || account.TagID < "26" || account.TagID > "51"):
+			check.fail(field+".TagID", account.TagID, "template tag 26-51")
+		case used[account.TagID]:
+			check.fail(field+".TagID", account.TagID, "unique tag")
+		}
+		used[account.TagID] = true
+	}
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
+	merchant := Merchant{}
+	if config.Merchant != nil {
+		merchant = *config.Merchant
+	}
+	merchant.Accounts = nil
+	for i, account := range config.Accounts {
+		tagID := account.TagID
+		if tagID == "" {
+			tagID = freeTag(schemes[i], used)
+			if tagID == "" {
+				check.fail(fmt.Sprintf("Accounts[%d].TagID", i), "(none)", "free tag of the "+schemes[i].Name+" scheme")
+				continue
+			}
+			used[tagID] = true
+		}
+
+		if schemes[i].Primitive() {
+			merchant.Accounts = append(merchant.Accounts, MerchantAccount{ID: tagID, Value: account.MerchantID})
+		} else {
+			merchant.AddAccount(tagID, account.AID, account.Fields...)
+		}
+	}
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
+	slices.SortStableFunc(merchant.Accounts, func(a, b MerchantAccount) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return merchant.Build()
+}
+
+// freeTag returns the first unused tag of a scheme, or an empty string if there is none.
+func freeTag(s aid.Scheme, used map[string]bool) string {
+	for _, tag := range s.Tags {
+		if !used[tag] {
+			return tag
+		}
+	}
+	if s.Primitive() {
+		return ""
+	}
+	for n := 26; n <= 51; n++ {
+		if tag := strconv.Itoa(n); !used[tag] {
+			return tag
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package generate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/aid"
+)
+
+func TestMultiScheme(t *testing.T) {
+	payload, err := MultiScheme(MultiSchemeConfig{
+		Accounts: []SchemeAccount{
+			{AID: aid.PromptPayAnyID, Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag(ProxyTypeMSISDN, "0066812345678")}},
+			{AID: aid.PromptPayBillPayment, Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag("01", "099400016550100"), thaiqrgo.Tag("02", "12345")}},
+			{AID: aid.PromptPayCrossBorder, Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag("01", "004"), thaiqrgo.Tag("02", "000000123456789")}},
+			{AID: aid.Visa, MerchantID: "4111111111111111"},
+			{AID: aid.Visa, MerchantID: "4000000000000002"},
+		},
+		Merchant: &Merchant{MCC: "5812", Name: "MY CAFE", City: "BANGKOK"},
+	})
+	if err != nil {
+		t.Fatalf("MultiScheme() error = %v", err)
+	}
+
+	qr, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []thaiqrgo.PaymentScheme{
+		{TagID: "02", AID: aid.Visa, Name: "Visa"},
+		{TagID: "03", AID: aid.Visa, Name: "Visa"},
+		{TagID: "29", AID: aid.PromptPayAnyID, Name: "PromptPay"},
+		{TagID: "30", AID: aid.PromptPayBillPayment, Name: "PromptPay Bill Payment"},
+		{TagID: "31", AID: aid.PromptPayCrossBorder, Name: "PromptPay Cross-Border"},
+	}
+	got := qr.Schemes()
+	if len(got) != len(want) {
+		t.Fatalf("Schemes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Schemes()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+	if qr.GetTagValue("02", "") != "4111111111111111" {
+		t.Errorf("Tag 02 = %v, want the first Visa merchant ID", qr.GetTagValue("02", ""))
+	}
+}
+
+func TestMultiScheme_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config MultiSchemeConfig
+		field  string
+	}{
+		{"no accounts", MultiSchemeConfig{}, "Accounts"},
+		{"unknown AID", MultiSchemeConfig{Accounts: []SchemeAccount{{AID: "A000000000"}}}, "Accounts[0].AID"},
+		{"card without merchant ID", MultiSchemeConfig{Accounts: []SchemeAccount{{AID: aid.Mastercard}}}, "Accounts[0].MerchantID"},
+		{"wrong card tag", MultiSchemeConfig{Accounts: []SchemeAccount{{AID: aid.JCB, MerchantID: "1", TagID: "02"}}}, "Accounts[0].TagID"},
+		{"template tag out of range", MultiSchemeConfig{Accounts: []SchemeAccount{{AID: aid.PromptPayAnyID, TagID: "52"}}}, "Accounts[0].TagID"},
+		{
+			"no free card tag",
+			MultiSchemeConfig{Accounts: []SchemeAccount{{AID: aid.JCB, MerchantID: "1"}, {AID: aid.JCB, MerchantID: "2"}, {AID: aid.JCB, MerchantID: "3"}}},
+			"Accounts[2].TagID",
+		},
+		{
+			"merchant accounts",
+			MultiSchemeConfig{Accounts: []SchemeAccount{{AID: aid.Visa, MerchantID: "1"}}, Merchant: (&Merchant{}).AddAccount("29", aidAnyID)},
+			"Merchant.Accounts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MultiScheme(tt.config)
+			var configErr *InvalidConfigError
+			if !errors.As(err, &configErr) || configErr.Field != tt.field {
+				t.Errorf("MultiScheme() error = %v, want %s error", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestMultiScheme_Size(t *testing.T) {
+	var accounts []SchemeAccount
+	for _, id := range []string{aid.Visa, aid.Mastercard, aid.Discover, aid.AmericanExpress, aid.JCB, aid.UnionPay} {
+		for range 2 {
+			accounts = append(accounts, SchemeAccount{AID: id, MerchantID: strings.Repeat("9", 40)})
+		}
+	}
+
+	_, err := MultiScheme(MultiSchemeConfig{Accounts: accounts})
+	var configErrs ConfigErrors
+	if !errors.As(err, &configErrs) || len(configErrs) != 1 || configErrs[0].Field != "Payload" {
+		t.Errorf("MultiScheme() error = %v, want ConfigErrors with a Payload error", err)
+	}
+}
@@ -0,0 +1,162 @@
+package generate
+
+import (
+	"strconv"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+// Signature limits.
+const (
+	// defaultSignatureTag is the root tag of the signature template
+	defaultSignatureTag = "90"
+
+	// referenceSignatureTag places the signature at the end of the reference label
+	referenceSignatureTag = "62.05"
+
+	// maxSignatureKeyIDLength is the maximum length of a key ID
+	maxSignatureKeyIDLength = 8
+
+	// minSignatureSecretLength is the minimum length of a secret in bytes
+	minSignatureSecretLength = 16
+)
+
+// SignatureConfig configures the signature of a QR code.
+type SignatureConfig struct {
+	// Key is the signing key
+	Key thaiqrgo.SignatureKey
+
+	// Tag is where the signature is stored: a root tag 80-99 holding a template
+	// identified by thaiqrgo.SignatureGUID (default: "90"), or "62.05" to append
+	// the signature to the reference label
+	Tag string
+
+	// Length is the signature length in bytes (default: thaiqrgo.DefaultSignatureLength)
+	Length int
+}
+
+// Sign adds a truncated HMAC-SHA256 signature over the payee, amount, reference and expiry
+// to a merchant QR code payload and recomputes its CRC (Tag 63).
+//
+// The signed data is described by thaiqrgo.SignatureMessage. Changing any of it,
+// e.g. lowering the amount or swapping the payee, invalidates the signature even
+// if the CRC is recomputed. Check signatures with thaiqrgo.SignatureVerifier.
+func Sign(payload string, config SignatureConfig) (string, error) {
+	var check configCheck
+	tagID := valueOr(config.Tag, defaultSignatureTag)
+	inReference := tagID == referenceSignatureTag
+	if !inReference && (len(tagID) != 2 || !isNumeric(tagID) || tagID < "80" || tagID > "99") {
+		check.fail("Tag", tagID, "tag ID 80-99 or 62.05")
+	}
+	if !inReference || config.Key.ID != "" {
+		if check.required("Key.ID", config.Key.ID) {
+			check.text("Key.ID", config.Key.ID, maxSignatureKeyIDLength, charsetAlphanumeric, isAlphanumericByte)
+		}
+	}
+	if len(config.Key.Secret) < minSignatureSecretLength {
+		check.fail("Key.Secret", strconv.Itoa(len(config.Key.Secret))+" bytes", "at least "+strconv.Itoa(minSignatureSecretLength)+" bytes")
+	}
+	length := config.Length
+	if length == 0 {
+		length = thaiqrgo.DefaultSignatureLength
+	}
+	if length < thaiqrgo.MinSignatureLength || length > thaiqrgo.MaxSignatureLength {
+		check.fail("Length", strconv.Itoa(length), strconv.Itoa(thaiqrgo.MinSignatureLength)+"-"+strconv.Itoa(thaiqrgo.MaxSignatureLength)+" bytes")
+	}
+
+	qr, err := thaiqrgo.Parse(payload, true, false)
+	if err != nil {
+		check.fail("Payload", payload, "valid QR code with CRC (Tag 63)")
+		return "", check.err()
+	}
+
+	var tags []thaiqrgo.TLVTag
+	for _, tag := range qr.GetTags() {
+		if tag.ID == "63" {
+			continue
+		}
+		if tag.ID == tagID {
+			check.fail("Tag", tagID, "unused tag")
+		}
+		if isSignatureTemplate(tag) {
+			check.fail("Payload", payload, "not signed")
+		}
+		tags = append(tags, tag)
+	}
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
+	label := qr.ReferenceLabel()
+	signature := config.Key.Sign(thaiqrgo.SignatureMessage(tags, label), length)
+
+	if inReference {
+		label += signature
+		if len(label) > maxReferenceLabelLength {
+			check.fail("Tag", label, "reference label with signature max length "+strconv.Itoa(maxReferenceLabelLength))
+		}
+		tags = withReferenceLabel(tags, label)
+	} else {
+		tags = append(tags, thaiqrgo.Tag(tagID, thaiqrgo.Encode([]thaiqrgo.TLVTag{
+			thaiqrgo.Tag("00", thaiqrgo.SignatureGUID),
+			thaiqrgo.Tag("01", config.Key.ID),
+			thaiqrgo.Tag("02", signature),
+		})))
+	}
+	for _, tag := range tags {
+		if len(tag.Value) > maxTemplateLength {
+			check.fail("Payload", tag.ID, "max length 99")
+		}
+	}
+	if err := check.err(); err != nil {
+		return "", err
+	}
+
+	result := thaiqrgo.WithCRCTag(thaiqrgo.Encode(tags), "63", true)
+	check.payloadSize(result)
+	if err := check.err(); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// isSignatureTemplate reports whether a root tag is a signature template.
+func isSignatureTemplate(tag thaiqrgo.TLVTag) bool {
+	if tag.ID < "80" || tag.ID > "99" {
+		return false
+	}
+	sub, err := thaiqrgo.Decode(tag.Value)
+	return err == nil && len(sub) > 0 && sub[0].ID == "00" && sub[0].Value == thaiqrgo.SignatureGUID
+}
+
+// withReferenceLabel sets the reference label (Tag 62.05), adding the additional
+// data field template if needed. Sub-tags stay in ID order.
+func withReferenceLabel(tags []thaiqrgo.TLVTag, label string) []thaiqrgo.TLVTag {
+	position := len(tags)
+	for i, tag := range tags {
+		if tag.ID == "62" {
+			sub, _ := thaiqrgo.Decode(tag.Value)
+			tags[i] = thaiqrgo.Tag("62", thaiqrgo.Encode(withSubTag(sub, thaiqrgo.Tag("05", label))))
+			return tags
+		}
+		if tag.ID > "62" && position == len(tags) {
+			position = i
+		}
+	}
+	tag := thaiqrgo.Tag("62", thaiqrgo.Encode([]thaiqrgo.TLVTag{thaiqrgo.Tag("05", label)}))
+	return append(tags[:position], append([]thaiqrgo.TLVTag{tag}, tags[position:]...)...)
+}
+
+// withSubTag replaces the sub-tag with the same ID or inserts it in ID order.
+func withSubTag(sub []thaiqrgo.TLVTag, tag thaiqrgo.TLVTag) []thaiqrgo.TLVTag {
+	for i := range sub {
+		if sub[i].ID == tag.ID {
+			sub[i] = tag
+			return sub
+		}
+		if sub[i].ID > tag.ID {
+			return append(sub[:i], append([]thaiqrgo.TLVTag{tag}, sub[i:]...)...)
+		}
+	}
+	return append(sub, tag)
+}
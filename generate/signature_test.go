@@ -0,0 +1,201 @@
+package generate
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klimakov/thai-qr-go"
+)
+
+var testSignatureKey = thaiqrgo.SignatureKey{ID: "K1", Secret: []byte("0123456789abcdef")}
+
+func TestSign(t *testing.T) {
+	amount := thaiqrgo.MustParseAmount("500")
+	payload, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: "0812345678", Amount: &amount})
+	if err != nil {
+		t.Fatalf("AnyID() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		config    SignatureConfig
+		verifier  thaiqrgo.SignatureVerifier
+		reference string
+	}{
+		{"template", SignatureConfig{Key: testSignatureKey}, thaiqrgo.SignatureVerifier{}, ""},
+		{"template tag 99", SignatureConfig{Key: testSignatureKey, Tag: "99", Length: 16}, thaiqrgo.SignatureVerifier{Length: 16}, ""},
+		{"reference", SignatureConfig{Key: testSignatureKey, Tag: "62.05", Length: 4}, thaiqrgo.SignatureVerifier{Reference: true, Length: 4}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := Sign(payload, tt.config)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			qr, err := thaiqrgo.Parse(signed, true, true)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			tt.verifier.Keys = []thaiqrgo.SignatureKey{testSignatureKey}
+			got, err := tt.verifier.Verify(qr)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got.KeyID != "K1" || got.Reference != tt.reference {
+				t.Errorf("Verify() = %+v, want key K1 and reference %q", got, tt.reference)
+			}
+			if _, err := Sign(signed, tt.config); err == nil && tt.config.Tag != "62.05" {
+				t.Error("Sign() of a signed payload should return error")
+			}
+		})
+	}
+}
+
+func TestSign_Tampered(t *testing.T) {
+	merchant := testMerchant()
+	qr, err := Dynamic(DynamicConfig{
+		Merchant:  merchant,
+		Reference: "ORDER-1001",
+		TTL:       15 * time.Minute,
+		Signature: &SignatureConfig{Key: testSignatureKey},
+	})
+	if err != nil {
+		t.Fatalf("Dynamic() error = %v", err)
+	}
+
+	// Regenerate the QR code with a lower amount, keeping the signature template.
+	lowered := thaiqrgo.MustParseAmount("1.00")
+	forged := *merchant
+	forged.Amount = &lowered
+	forged.PointOfInitiation = "12"
+	parsed, err := thaiqrgo.Parse(qr.Payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	forged.AdditionalData = parsed.GetTag("62", "").SubTags
+	forged.Unreserved = []thaiqrgo.TLVTag{*parsed.GetTag("90", "")}
+	payload, err := forged.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	verifier := thaiqrgo.SignatureVerifier{Keys: []thaiqrgo.SignatureKey{testSignatureKey}}
+	if _, err := verifier.Verify(parsed); err != nil {
+		t.Errorf("Verify() of the issued QR code error = %v", err)
+	}
+	tampered, err := thaiqrgo.Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := verifier.Verify(tampered); !errors.Is(err, thaiqrgo.ErrSignatureMismatch) {
+		t.Errorf("Verify() of the forged QR code error = %v, want %v", err, thaiqrgo.ErrSignatureMismatch)
+	}
+}
+
+func TestSign_TamperedExpiry(t *testing.T) {
+	issuedAt := time.Date(2025, time.March, 1, 10, 0, 0, 0, time.UTC)
+	qr, err := Dynamic(DynamicConfig{
+		Merchant:  testMerchant(),
+		Reference: "ORDER-1001",
+		TTL:       15 * time.Minute,
+		IssuedAt:  issuedAt,
+		Signature: &SignatureConfig{Key: testSignatureKey},
+	})
+	if err != nil {
+		t.Fatalf("Dynamic() error = %v", err)
+	}
+
+	// Move the expiry a year ahead and recompute the CRC
+	expiry := qr.ExpiresAt.Format(thaiqrgo.ExpiryLayout)
+	body := strings.Replace(qr.Payload[:len(qr.Payload)-8], expiry, "2026"+expiry[4:], 1)
+	revived := thaiqrgo.WithCRCTag(body, "63", true)
+	tampered, err := thaiqrgo.Parse(revived, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, ok := tampered.ExpiresAt(); !ok || got.Year() != 2026 {
+		t.Fatalf("ExpiresAt() = %v, %v, want the changed expiry", got, ok)
+	}
+
+	verifier := thaiqrgo.SignatureVerifier{Keys: []thaiqrgo.SignatureKey{testSignatureKey}}
+	if _, err := verifier.Verify(tampered); !errors.Is(err, thaiqrgo.ErrSignatureMismatch) {
+		t.Errorf("Verify() with a changed expiry error = %v, want %v", err, thaiqrgo.ErrSignatureMismatch)
+	}
+}
+
+func TestSign_InvalidConfig(t *testing.T) {
+	payload, err := AnyID(AnyIDConfig{Type: "MSISDN", Target: "0812345678"})
+	if err != nil {
+		t.Fatalf("AnyID() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		config SignatureConfig
+		field  string
+	}{
+		{"no key ID", SignatureConfig{Key: thaiqrgo.SignatureKey{Secret: testSignatureKey.Secret}}, "Key.ID"},
+		{"long key ID", SignatureConfig{Key: thaiqrgo.SignatureKey{ID: "KEY-2025-01", Secret: testSignatureKey.Secret}}, "Key.ID"},
+		{"short secret", SignatureConfig{Key: thaiqrgo.SignatureKey{ID: "K1", Secret: []byte("secret")}}, "Key.Secret"},
+		{"short signature", SignatureConfig{Key: testSignatureKey, Length: 2}, "Length"},
+		{"reserved tag", SignatureConfig{Key: testSignatureKey, Tag: "63"}, "Tag"},
+		{"long reference", SignatureConfig{Key: testSignatureKey, Tag: "62.05", Length: 16}, "Tag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Sign(payload, tt.config)
+			var configErr *InvalidConfigError
+			if !errors.As(err, &configErr) {
+				t.Fatalf("Sign() error = %v, want InvalidConfigError", err)
+			}
+			if configErr.Field != tt.field {
+				t.Errorf("Sign() error field = %v, want %v", configErr.Field, tt.field)
+			}
+			if strings.Contains(err.Error(), string(testSignatureKey.Secret)) {
+				t.Errorf("Sign() error = %v, must not contain the secret", err)
+			}
+		})
+	}
+
+	if _, err := Sign(payload[:len(payload)-1]+"0", SignatureConfig{Key: testSignatureKey}); err == nil {
+		t.Error("Sign() with a bad CRC should return error")
+	}
+
+	_, err = Dynamic(DynamicConfig{
+		Merchant:  testMerchant(),
+		Reference: "ORDER-1001",
+		TTL:       time.Minute,
+		Signature: &SignatureConfig{Key: testSignatureKey, Tag: "62.05"},
+	})
+	var configErr *InvalidConfigError
+	if !errors.As(err, &configErr) || configErr.Field != "Signature.Tag" {
+		t.Errorf("Dynamic() error = %v, want Signature.Tag error", err)
+	}
+}
+
+func TestSign_Size(t *testing.T) {
+	merchant := testMerchant()
+	base, err := merchant.Build()
+	if err != nil {
+		t.Fatalf("Merchant.Build() error = %v", err)
+	}
+	// Fill the payload up to 500 bytes, so that the signature does not fit
+	for id, pad := 80, 500-len(base); pad > 4; id++ {
+		n := min(pad-4, 99)
+		merchant.Unreserved = append(merchant.Unreserved, thaiqrgo.Tag(strconv.Itoa(id), strings.Repeat("X", n)))
+		pad -= n + 4
+	}
+	payload, err := merchant.Build()
+	if err != nil {
+		t.Fatalf("Merchant.Build() error = %v", err)
+	}
+
+	_, err = Sign(payload, SignatureConfig{Key: testSignatureKey, Tag: "99"})
+	var configErrs ConfigErrors
+	if !errors.As(err, &configErrs) || len(configErrs) != 1 || configErrs[0].Field != "Payload" {
+		t.Errorf("Sign() error = %v, want ConfigErrors with a Payload error", err)
+	}
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Check digit schemes for bill payment references.
+const (
+	// CheckDigitMod10 is the Luhn algorithm
+	CheckDigitMod10 = "mod10"
+
+	// CheckDigitMod11 is a modulus 11 check with weights 2-7 from the right.
+	// Remainders that would give 10 or 11 map to 0.
+	CheckDigitMod11 = "mod11"
+)
+
+var (
+	schemesMu sync.RWMutex
+	schemes   = map[string]func(digits string) (byte, error){
+		CheckDigitMod10: func(digits string) (byte, error) { return luhn(digits), nil },
+		CheckDigitMod11: func(digits string) (byte, error) { return mod11(digits), nil },
+	}
+)
+
+// RegisterCheckDigit adds a named check digit scheme, or replaces the scheme
+// with the same name. The function is only called with non-empty digit strings.
+// The built-in schemes cannot be replaced.
+//
+// This function is exported for use within the module.
+func RegisterCheckDigit(scheme string, compute func(digits string) (byte, error)) error {
+	switch {
+	case scheme == "":
+		return fmt.Errorf("invalid check digit scheme name: %q", scheme)
+	case scheme == CheckDigitMod10 || scheme == CheckDigitMod11:
+		return fmt.Errorf("check digit scheme %q is built in and cannot be replaced", scheme)
+	case compute == nil:
+		return fmt.Errorf("invalid check digit scheme %q: no function", scheme)
+	}
+
+	schemesMu.Lock()
+	defer schemesMu.Unlock()
+	schemes[scheme] = compute
+	return nil
+}
+
+// CheckDigitFunc returns the function of a named check digit scheme.
+//
+// This function is exported for use within the module.
+func CheckDigitFunc(scheme string) (func(digits string) (byte, error), bool) {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	compute, ok := schemes[scheme]
+	return compute, ok
+}
+
+// HasCheckDigit reports whether a check digit scheme with the given name exists.
+//
+// This function is exported for use within the module.
+func HasCheckDigit(scheme string) bool {
+	schemesMu.RLock()
+	defer schemesMu.RUnlock()
+	_, ok := schemes[scheme]
+	return ok
+}
+
+// CheckDigit calculates the check digit of digits using the named scheme.
+//
+// This function is exported for use within the module.
+func CheckDigit(scheme, digits string) (byte, error) {
+	schemesMu.RLock()
+	compute, ok := schemes[scheme]
+	schemesMu.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("unknown check digit scheme: %q", scheme)
+	}
+
+	if digits == "" || !isDigits(digits) {
+		return 0, fmt.Errorf("invalid check digit input %q: must contain digits only", digits)
+	}
+	return compute(digits)
+}
+
+// VerifyCheckDigit reports whether the last character of value is the check
+// digit of the preceding digits using the named scheme.
+//
+// This function is exported for use within the module.
+func VerifyCheckDigit(scheme, value string) bool {
+	if len(value) < 2 {
+		return false
+	}
+	check, err := CheckDigit(scheme, value[:len(value)-1])
+	return err == nil && value[len(value)-1] == check
+}
+
+// luhn calculates the Luhn (mod 10) check digit.
+func luhn(digits string) byte {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// mod11 calculates the modulus 11 check digit with weights 2-7 from the right.
+func mod11(digits string) byte {
+	sum := 0
+	weight := 2
+	for i := len(digits) - 1; i >= 0; i-- {
+		sum += int(digits[i]-'0') * weight
+		weight++
+		if weight > 7 {
+			weight = 2
+		}
+	}
+	return byte('0' + (11-sum%11)%11%10)
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package internal
+
+import "testing"
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		scheme, digits string
+		want           byte
+	}{
+		{CheckDigitMod10, "7992739871", '3'},
+		{CheckDigitMod10, "4111111111111111"[:15], '1'},
+		{CheckDigitMod10, "0", '0'},
+		{CheckDigitMod11, "123456", '0'},
+		{CheckDigitMod11, "261533", '9'},
+	}
+
+	for _, tt := range tests {
+		got, err := CheckDigit(tt.scheme, tt.digits)
+		if err != nil {
+			t.Fatalf("CheckDigit(%q, %q) error = %v", tt.scheme, tt.digits, err)
+		}
+		if got != tt.want {
+			t.Errorf("CheckDigit(%q, %q) = %c, want %c", tt.scheme, tt.digits, got, tt.want)
+		}
+		if !VerifyCheckDigit(tt.scheme, tt.digits+string(tt.want)) {
+			t.Errorf("VerifyCheckDigit(%q, %q) = false, want true", tt.scheme, tt.digits+string(tt.want))
+		}
+	}
+
+	if _, err := CheckDigit("mod97", "123"); err == nil {
+		t.Error("CheckDigit() should return error for unknown scheme")
+	}
+	if _, err := CheckDigit(CheckDigitMod10, "12A"); err == nil {
+		t.Error("CheckDigit() should return error for non-digits")
+	}
+	if VerifyCheckDigit(CheckDigitMod10, "79927398710") {
+		t.Error("VerifyCheckDigit() = true for wrong check digit, want false")
+	}
+}
@@ -2,19 +2,61 @@ package internal
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
 )
 
-// EncodeTag81 generates a UCS-2-like hex string for Tag 81.
+// EncodeTag81 generates a UTF-16 hex string for Tag 81.
 //
 // This method is equivalent to:
 // Buffer.from(message, 'utf16le').swap16().toString('hex').toUpperCase()
 //
-// It encodes each character's Unicode code point as a 4-digit uppercase hex string.
+// It encodes each UTF-16 code unit as a 4-digit uppercase hex string, so
+// characters outside the Basic Multilingual Plane (e.g. emoji) take two
+// groups (a surrogate pair).
 // This function is exported for use within the module.
 func EncodeTag81(message string) string {
-	var result string
-	for _, c := range message {
-		result += fmt.Sprintf("%04X", c)
+	units := utf16.Encode([]rune(message))
+
+	var result strings.Builder
+	result.Grow(len(units) * 4)
+	for _, unit := range units {
+		fmt.Fprintf(&result, "%04X", unit)
+	}
+	return result.String()
+}
+
+// DecodeTag81 reverses EncodeTag81.
+//
+// The input must consist of 4-digit hex groups, one per UTF-16 code unit,
+// with surrogates only in valid pairs.
+// This function is exported for use within the module.
+func DecodeTag81(hexStr string) (string, error) {
+	if len(hexStr)%4 != 0 {
+		return "", fmt.Errorf("invalid Tag 81 length: %d is not a multiple of 4", len(hexStr))
+	}
+
+	units := make([]uint16, 0, len(hexStr)/4)
+	for i := 0; i < len(hexStr); i += 4 {
+		unit, err := strconv.ParseUint(hexStr[i:i+4], 16, 16)
+		if err != nil {
+			return "", fmt.Errorf("invalid Tag 81 character at position %d: %w", i, err)
+		}
+		units = append(units, uint16(unit))
+	}
+
+	for i := 0; i < len(units); i++ {
+		r := rune(units[i])
+		if !utf16.IsSurrogate(r) {
+			continue
+		}
+		if i+1 < len(units) && utf16.DecodeRune(r, rune(units[i+1])) != unicode.ReplacementChar {
+			i++
+			continue
+		}
+		return "", fmt.Errorf("invalid Tag 81 character at position %d: unpaired surrogate %04X", i*4, r)
 	}
-	return result
+	return string(utf16.Decode(units)), nil
 }
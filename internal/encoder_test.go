@@ -28,6 +28,11 @@ func TestEncodeTag81(t *testing.T) {
 			message: "ส",
 			want:    "0E2A",
 		},
+		{
+			name:    "emoji as surrogate pair",
+			message: "Hi😀",
+			want:    "00480069D83DDE00",
+		},
 	}
 
 	for _, tt := range tests {
@@ -39,3 +44,35 @@ func TestEncodeTag81(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeTag81(t *testing.T) {
+	tests := []struct {
+		name    string
+		hexStr  string
+		want    string
+		wantErr bool
+	}{
+		{name: "simple message", hexStr: "00480069", want: "Hi"},
+		{name: "empty string", hexStr: "", want: ""},
+		{name: "lowercase hex", hexStr: "006c006f", want: "lo"},
+		{name: "unicode characters", hexStr: "0E2A", want: "ส"},
+		{name: "truncated", hexStr: "004", wantErr: true},
+		{name: "not hex", hexStr: "00ZZ", wantErr: true},
+		{name: "surrogate pair", hexStr: "D83DDE00", want: "😀"},
+		{name: "lone high surrogate", hexStr: "D83D0041", wantErr: true},
+		{name: "lone low surrogate", hexStr: "DE00", wantErr: true},
+		{name: "truncated surrogate pair", hexStr: "0041D83D", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTag81(tt.hexStr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeTag81(%q) error = %v, wantErr %v", tt.hexStr, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeTag81(%q) = %v, want %v", tt.hexStr, got, tt.want)
+			}
+		})
+	}
+}
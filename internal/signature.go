@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// HMACSignature returns the first length bytes of the HMAC-SHA256 of the message
+// as an uppercase hexadecimal string.
+//
+// This function is exported for use within the module.
+func HMACSignature(secret []byte, message string, length int) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(message))
+	sum := mac.Sum(nil)
+	if length > len(sum) {
+		length = len(sum)
+	}
+	return strings.ToUpper(hex.EncodeToString(sum[:length]))
+}
@@ -0,0 +1,23 @@
+package internal
+
+import "testing"
+
+func TestHMACSignature(t *testing.T) {
+	// RFC 4231 test case 2
+	want := "5BDCC146BF60754E6A042426089575C75A003F089D2739839DEC58B964EC3843"
+
+	tests := []struct {
+		length int
+		want   string
+	}{
+		{4, want[:8]},
+		{8, want[:16]},
+		{32, want},
+		{64, want},
+	}
+	for _, tt := range tests {
+		if got := HMACSignature([]byte("Jefe"), "what do ya want for nothing?", tt.length); got != tt.want {
+			t.Errorf("HMACSignature(%d) = %v, want %v", tt.length, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package thaiqrgo
+
+import (
+	"fmt"
+
+	"github.com/klimakov/thai-qr-go/aid"
+	"github.com/klimakov/thai-qr-go/bank"
+	"github.com/klimakov/thai-qr-go/biller"
+	"github.com/klimakov/thai-qr-go/ewallet"
+	"github.com/klimakov/thai-qr-go/phone"
+	"github.com/klimakov/thai-qr-go/thaiid"
+)
+
+// PromptPay application identifiers checked by lint.
+const (
+	aidPromptPayAnyID       = aid.PromptPayAnyID
+	aidPromptPayBillPayment = aid.PromptPayBillPayment
+	aidPromptPayCrossBorder = aid.PromptPayCrossBorder
+)
+
+// lint reports values that are well-formed but suspicious, such as a NATID
+// proxy or biller ID with a wrong tax ID check digit, an MSISDN proxy that
+// is not a Thai mobile number, a malformed EWALLETID proxy,
+// a BANKACC proxy that does not match the account layout of a known bank,
+// Bill Payment data that breaks the rules of its biller (as an error), a cross-border
+// merchant QR code missing mandatory fields, or an e-wallet personal message
+// (Tag 81) that is not valid UTF-16 hex.
+func (p *parser) lint(tags []TLVTag) {
+	offset := 0
+	eWallet := false
+	for _, tag := range tags {
+		valueOffset := offset + 4
+		offset += 4 + len(tag.Value)
+
+		sub := tag.SubTags
+		if len(sub) == 0 {
+			if decoded, _, err := decodeTLV(tag.Value, p.opts.LengthMode); err == nil {
+				sub = decoded
+			}
+		}
+
+		switch {
+		case tag.ID == "29" && subTagValue(sub, "00") == aidPromptPayAnyID:
+			p.lintSubTag(sub, valueOffset, "29", "01", validMSISDN,
+				"MSISDN proxy is not a Thai mobile number")
+			p.lintSubTag(sub, valueOffset, "29", "02", thaiid.Valid,
+				"NATID proxy is not a valid national ID or tax ID")
+			p.lintSubTag(sub, valueOffset, "29", "03", validEWalletID,
+				"EWALLETID proxy is not a valid e-wallet ID")
+			p.lintSubTag(sub, valueOffset, "29", "04", validBankAccount,
+				"BANKACC proxy is not an account of a known bank")
+			eWallet = subTagValue(sub, "03") != ""
+		case tag.ID == "30" && subTagValue(sub, "00") == aidPromptPayBillPayment:
+			p.lintSubTag(sub, valueOffset, "30", "01", validBillerID,
+				"biller ID does not start with a valid tax ID")
+			p.lintBiller(tags, sub, valueOffset)
+		case tag.ID == "30" && subTagValue(sub, "00") == aidPromptPayCrossBorder:
+			p.lintCrossBorder(tags, valueOffset)
+		case tag.ID == "81" && eWallet:
+			if _, err := DecodePersonalMessage(tag.Value); err != nil {
+				p.warn(valueOffset, "81", "personal message is not valid UTF-16 hex: "+err.Error())
+			}
+		}
+	}
+}
+
+// lintBiller checks Bill Payment references and amount against the biller
+// directory. Unlike the other checks, broken rules are errors, as they are
+// for BOT Barcodes and the generators.
+func (p *parser) lintBiller(tags, sub []TLVTag, offset int) {
+	directory := p.opts.Billers
+	if directory == nil {
+		directory = biller.Default
+	}
+
+	var ref2 *string
+	for _, tag := range sub {
+		if tag.ID == "03" {
+			ref2 = &tag.Value
+		}
+	}
+	hasAmount := false
+	for _, tag := range tags {
+		hasAmount = hasAmount || tag.ID == "54"
+	}
+
+	err := directory.Validate(subTagValue(sub, "01"), subTagValue(sub, "02"), ref2, hasAmount)
+	if ruleErr, ok := err.(*biller.RuleError); ok {
+		paths := map[string]string{"BillerID": "30.01", "Ref1": "30.02", "Ref2": "30.03", "Amount": "54"}
+		p.fail(offset, paths[ruleErr.Field], fmt.Errorf("invalid Bill Payment QR: %w", ruleErr))
+	}
+}
+
+// lintCrossBorder checks the root fields a cross-border merchant QR code must
+// have: merchant category code, name and city, in THB. The sub-tags of the
+// template are not checked.
+func (p *parser) lintCrossBorder(tags []TLVTag, offset int) {
+	rootChecks := []struct {
+		id      string
+		valid   func(string) bool
+		message string
+	}{
+		{"52", func(v string) bool { return v != "" && v != "0000" }, "cross-border merchant QR needs a merchant category code"},
+		{"53", func(v string) bool { return v == "764" }, "cross-border merchant QR must be in THB (764)"},
+		{"58", func(v string) bool { return v == "TH" }, "cross-border merchant QR must have country TH"},
+		{"59", func(v string) bool { return v != "" }, "cross-border merchant QR needs a merchant name"},
+		{"60", func(v string) bool { return v != "" }, "cross-border merchant QR needs a merchant city"},
+	}
+	for _, check := range rootChecks {
+		tagOffset, value := offset-4, ""
+		pos := 0
+		for _, tag := range tags {
+			if tag.ID == check.id {
+				tagOffset, value = pos, tag.Value
+				break
+			}
+			pos += 4 + len(tag.Value)
+		}
+		if !check.valid(value) {
+			p.warn(tagOffset, check.id, check.message)
+		}
+	}
+}
+
+// lintSubTag records a warning if a sub-tag value fails the check.
+func (p *parser) lintSubTag(sub []TLVTag, offset int, tagID, subTagID string, valid func(string) bool, message string) {
+	for _, tag := range sub {
+		if tag.ID == subTagID {
+			if !valid(tag.Value) {
+				p.warn(offset+4, tagID+"."+subTagID, message)
+			}
+			return
+		}
+		offset += 4 + len(tag.Value)
+	}
+}
+
+// subTagValue returns the value of the first sub-tag with the given ID.
+func subTagValue(sub []TLVTag, id string) string {
+	for _, tag := range sub {
+		if tag.ID == id {
+			return tag.Value
+		}
+	}
+	return ""
+}
+
+func validBillerID(billerID string) bool {
+	return thaiid.ValidateBillerID(billerID) == nil
+}
+
+func validMSISDN(proxy string) bool {
+	_, err := phone.FromProxy(proxy)
+	return err == nil
+}
+
+func validBankAccount(proxy string) bool {
+	_, err := bank.ParseProxy(proxy)
+	return err == nil
+}
+
+// validEWalletID accepts IDs of providers missing from the registry, and
+// checks the layout of registered ones.
+func validEWalletID(id string) bool {
+	if ewallet.ValidateFormat(id) != nil {
+		return false
+	}
+	if _, ok := ewallet.Lookup(id[:3]); ok {
+		return ewallet.Validate(id) == nil
+	}
+	return true
+}
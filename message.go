@@ -0,0 +1,44 @@
+package thaiqrgo
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/klimakov/thai-qr-go/internal"
+)
+
+// MaxPersonalMessageLength is the maximum length of a personal message (Tag 81)
+// in UTF-16 code units. Each unit takes 4 hex digits of the 99-character value,
+// and characters outside the Basic Multilingual Plane (e.g. emoji) take two units.
+const MaxPersonalMessageLength = 24
+
+// EncodePersonalMessage encodes a personal message as a Tag 81 value:
+// the uppercase hex digits of its UTF-16 code units.
+//
+// Returns an error if the message is longer than MaxPersonalMessageLength code units.
+func EncodePersonalMessage(message string) (string, error) {
+	if n := len(utf16.Encode([]rune(message))); n > MaxPersonalMessageLength {
+		return "", fmt.Errorf("personal message too long: %d UTF-16 code units, max %d", n, MaxPersonalMessageLength)
+	}
+	return internal.EncodeTag81(message), nil
+}
+
+// DecodePersonalMessage decodes a Tag 81 value back to text.
+//
+// Returns an error if the value is not a sequence of 4-digit hex groups
+// forming valid UTF-16.
+func DecodePersonalMessage(value string) (string, error) {
+	return internal.DecodeTag81(value)
+}
+
+// PersonalMessage returns the decoded personal message (Tag 81), as used by TrueMoney.
+//
+// Returns an empty string if the QR code has no personal message,
+// and an error if the message is malformed.
+func (q *EMVCoQR) PersonalMessage() (string, error) {
+	tag := q.GetTag("81", "")
+	if tag == nil {
+		return "", nil
+	}
+	return DecodePersonalMessage(tag.Value)
+}
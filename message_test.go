@@ -0,0 +1,88 @@
+package thaiqrgo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodePersonalMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+		wantErr bool
+	}{
+		{name: "ascii", message: "Hi", want: "00480069"},
+		{name: "thai", message: "สวัสดี", want: "0E2A0E270E310E2A0E140E35"},
+		{name: "emoji", message: "🎉", want: "D83CDF89"},
+		{name: "max length", message: strings.Repeat("a", MaxPersonalMessageLength), want: strings.Repeat("0061", MaxPersonalMessageLength)},
+		{name: "too long", message: strings.Repeat("a", MaxPersonalMessageLength+1), wantErr: true},
+		{name: "too long with emoji", message: strings.Repeat("a", MaxPersonalMessageLength-1) + "🎉", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EncodePersonalMessage(tt.message)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncodePersonalMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EncodePersonalMessage() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr {
+				return
+			}
+			decoded, err := DecodePersonalMessage(got)
+			if err != nil || decoded != tt.message {
+				t.Errorf("DecodePersonalMessage() = %q, %v, want %q", decoded, err, tt.message)
+			}
+		})
+	}
+}
+
+func TestEMVCoQR_PersonalMessage(t *testing.T) {
+	build := func(message string) string {
+		tags := []TLVTag{
+			Tag("00", "01"),
+			Tag("01", "11"),
+			Tag("29", Encode([]TLVTag{Tag("00", aidPromptPayAnyID), Tag("03", "140000812345678")})),
+			Tag("53", "764"),
+			Tag("58", "TH"),
+		}
+		if message != "" {
+			tags = append(tags, Tag("81", message))
+		}
+		return WithCRCTag(Encode(tags), "63", true)
+	}
+
+	qr, err := Parse(build("00480069D83DDE00"), true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, err := qr.PersonalMessage(); err != nil || got != "Hi😀" {
+		t.Errorf("PersonalMessage() = %q, %v, want %q", got, err, "Hi😀")
+	}
+	if len(qr.Diagnostics().Warnings()) != 0 {
+		t.Errorf("Diagnostics() = %v, want no warnings", qr.Diagnostics())
+	}
+
+	qr, err = Parse(build("0048D83D"), true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := qr.PersonalMessage(); err == nil {
+		t.Error("PersonalMessage() with unpaired surrogate error = nil, want error")
+	}
+	warnings := qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "81" {
+		t.Errorf("Diagnostics().Warnings() = %v, want one tag 81 warning", warnings)
+	}
+
+	qr, err = Parse(build(""), true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, err := qr.PersonalMessage(); err != nil || got != "" {
+		t.Errorf("PersonalMessage() without Tag 81 = %q, %v, want empty", got, err)
+	}
+}
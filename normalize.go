@@ -0,0 +1,188 @@
+package thaiqrgo
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// RemovalKind identifies what Normalize stripped from a payload.
+type RemovalKind string
+
+// RemovalKind values reported by Normalize.
+const (
+	RemovedBOM                   RemovalKind = "bom"                    // Byte order mark
+	RemovedAIMPrefix             RemovalKind = "aim_prefix"             // AIM symbology identifier (e.g. "]Q1")
+	RemovedSurroundingWhitespace RemovalKind = "surrounding_whitespace" // Leading/trailing whitespace, CR and LF
+	RemovedEmbeddedWhitespace    RemovalKind = "embedded_whitespace"    // Whitespace inside an EMVCo payload
+	RemovedURL                   RemovalKind = "url"                    // URL wrapping the payload
+	RemovedDataURI               RemovalKind = "data_uri"               // data: URI wrapping the payload
+)
+
+// Removal describes a piece of input that Normalize removed.
+type Removal struct {
+	// Kind is the type of the removed input
+	Kind RemovalKind
+
+	// Text is the removed input
+	Text string
+}
+
+// NormalizeResult is the result of Normalize.
+type NormalizeResult struct {
+	// Payload is the cleaned payload
+	Payload string
+
+	// Removed lists what was removed, in the order it was removed
+	Removed []Removal
+}
+
+const bom = "\uFEFF"
+
+var aimPrefixPattern = regexp.MustCompile(`^\][A-Za-z][0-9A-Za-z]`)
+
+// Normalize cleans up a payload string as received from a scanner, camera SDK or chat app.
+//
+// It removes a byte order mark, AIM symbology identifiers (e.g. "]Q1"),
+// surrounding whitespace including trailing CR/LF, and unwraps payloads embedded
+// in URLs or data: URIs. Whitespace inside an EMVCo payload is removed only if the
+// payload is not valid TLV with it and is valid without it, so that spaces in
+// merchant names are kept. BOT Barcode separators are never touched.
+func Normalize(raw string) NormalizeResult {
+	n := &normalizer{payload: raw}
+
+	n.trimBOM()
+	n.trimSpace()
+	if prefix := aimPrefixPattern.FindString(n.payload); prefix != "" {
+		n.remove(RemovedAIMPrefix, prefix, n.payload[len(prefix):])
+		n.trimSpace()
+	}
+
+	n.unwrapDataURI()
+	n.unwrapURL()
+	n.removeEmbeddedWhitespace()
+
+	return NormalizeResult{Payload: n.payload, Removed: n.removed}
+}
+
+// normalizer holds the state of a single Normalize call.
+type normalizer struct {
+	payload string
+	removed []Removal
+}
+
+func (n *normalizer) remove(kind RemovalKind, text, payload string) {
+	n.removed = append(n.removed, Removal{Kind: kind, Text: text})
+	n.payload = payload
+}
+
+func (n *normalizer) trimBOM() {
+	if strings.HasPrefix(n.payload, bom) {
+		n.remove(RemovedBOM, bom, strings.TrimPrefix(n.payload, bom))
+	}
+}
+
+func (n *normalizer) trimSpace() {
+	trimmed := strings.TrimSpace(n.payload)
+	if trimmed == n.payload {
+		return
+	}
+
+	start := strings.Index(n.payload, trimmed)
+	removed := n.payload[:start] + n.payload[start+len(trimmed):]
+	n.remove(RemovedSurroundingWhitespace, removed, trimmed)
+}
+
+// unwrapDataURI extracts the payload from a data: URI.
+func (n *normalizer) unwrapDataURI() {
+	if !strings.HasPrefix(strings.ToLower(n.payload), "data:") {
+		return
+	}
+
+	header, data, ok := strings.Cut(n.payload, ",")
+	if !ok {
+		return
+	}
+
+	var payload string
+	if strings.HasSuffix(strings.ToLower(header), ";base64") {
+		decoded, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			return
+		}
+		payload = string(decoded)
+	} else {
+		decoded, err := url.PathUnescape(data)
+		if err != nil {
+			return
+		}
+		payload = decoded
+	}
+
+	n.remove(RemovedDataURI, header+",", payload)
+	n.trimSpace()
+}
+
+// unwrapURL extracts the payload from a URL query parameter or path segment.
+func (n *normalizer) unwrapURL() {
+	u, err := url.Parse(n.payload)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+		return
+	}
+
+	// Each candidate keeps its raw (escaped) form, which is what the URL holds
+	type candidate struct{ raw, value string }
+	var candidates []candidate
+	for _, param := range strings.Split(u.RawQuery, "&") {
+		_, value, _ := strings.Cut(param, "=")
+		if unescaped, err := url.QueryUnescape(value); err == nil {
+			candidates = append(candidates, candidate{value, unescaped})
+		}
+	}
+	for _, segment := range strings.Split(u.EscapedPath(), "/") {
+		if unescaped, err := url.PathUnescape(segment); err == nil {
+			candidates = append(candidates, candidate{segment, unescaped})
+		}
+	}
+	candidates = append(candidates, candidate{u.EscapedFragment(), u.Fragment})
+
+	for _, c := range candidates {
+		value := strings.TrimSpace(c.value)
+		if looksLikePayload(value) {
+			n.remove(RemovedURL, strings.Replace(n.payload, c.raw, "", 1), value)
+			return
+		}
+	}
+}
+
+// removeEmbeddedWhitespace strips whitespace inside an EMVCo payload
+// if that is what breaks its TLV structure.
+func (n *normalizer) removeEmbeddedWhitespace() {
+	if strings.HasPrefix(n.payload, "|") || !strings.ContainsFunc(n.payload, unicode.IsSpace) {
+		return
+	}
+	if _, _, err := decodeTLV(n.payload, LengthBytes); err == nil {
+		return
+	}
+
+	var kept, removed strings.Builder
+	for _, r := range n.payload {
+		if unicode.IsSpace(r) {
+			removed.WriteRune(r)
+			continue
+		}
+		kept.WriteRune(r)
+	}
+
+	if _, _, err := decodeTLV(kept.String(), LengthBytes); err != nil {
+		return
+	}
+	n.remove(RemovedEmbeddedWhitespace, removed.String(), kept.String())
+}
+
+// looksLikePayload reports whether s could be an EMVCo QR or BOT Barcode payload.
+func looksLikePayload(s string) bool {
+	return strings.HasPrefix(s, "|") || (tlvPattern.MatchString(s) && strings.HasPrefix(s, "00"))
+}
@@ -0,0 +1,127 @@
+package thaiqrgo
+
+import "testing"
+
+const normalizeTestPayload = "00020101021229370016A0000006770101110113006680111111153037645802TH540520.15630442BE"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		raw   string
+		want  string
+		kinds []RemovalKind
+		text  string // text of the last removal, if set
+	}{
+		{
+			name: "clean payload",
+			raw:  normalizeTestPayload,
+			want: normalizeTestPayload,
+		},
+		{
+			name:  "AIM prefix and trailing CR LF",
+			raw:   "]Q1" + normalizeTestPayload + "\r\n",
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedSurroundingWhitespace, RemovedAIMPrefix},
+		},
+		{
+			name:  "BOM",
+			raw:   "\uFEFF" + normalizeTestPayload,
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedBOM},
+		},
+		{
+			name:  "embedded line breaks",
+			raw:   normalizeTestPayload[:40] + "\n" + normalizeTestPayload[40:80] + " " + normalizeTestPayload[80:],
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedEmbeddedWhitespace},
+		},
+		{
+			name: "spaces in merchant name are kept",
+			raw:  "0002015907MY SHOP5802TH",
+			want: "0002015907MY SHOP5802TH",
+		},
+		{
+			name:  "URL query parameter",
+			raw:   "https://example.com/pay?lang=th&qr=" + normalizeTestPayload,
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedURL},
+		},
+		{
+			name:  "URL query parameter with escapes",
+			raw:   "https://example.com/pay?qr=%30" + normalizeTestPayload[1:] + "&lang=th",
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedURL},
+			text:  "https://example.com/pay?qr=&lang=th",
+		},
+		{
+			name:  "URL path segment",
+			raw:   "https://example.com/qr/" + normalizeTestPayload,
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedURL},
+		},
+		{
+			name:  "data URI",
+			raw:   "data:text/plain," + normalizeTestPayload,
+			want:  normalizeTestPayload,
+			kinds: []RemovalKind{RemovedDataURI},
+		},
+		{
+			name:  "base64 data URI",
+			raw:   "data:text/plain;base64,fDA5OTk5OTk5OTk5OTk5MA0xMTEyMjIzMzM0NDQNDTA=",
+			want:  "|099999999999990\r111222333444\r\r0",
+			kinds: []RemovalKind{RemovedDataURI},
+		},
+		{
+			name:  "barcode with trailing CR",
+			raw:   "]C0|099999999999990\r111222333444\r\r0\r",
+			want:  "|099999999999990\r111222333444\r\r0",
+			kinds: []RemovalKind{RemovedSurroundingWhitespace, RemovedAIMPrefix},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(tt.raw)
+			if got.Payload != tt.want {
+				t.Errorf("Normalize() Payload = %q, want %q", got.Payload, tt.want)
+			}
+			if len(got.Removed) != len(tt.kinds) {
+				t.Fatalf("Normalize() Removed = %v, want kinds %v", got.Removed, tt.kinds)
+			}
+			for i, kind := range tt.kinds {
+				if got.Removed[i].Kind != kind {
+					t.Errorf("Normalize() Removed[%d].Kind = %v, want %v", i, got.Removed[i].Kind, kind)
+				}
+			}
+			if tt.text != "" && got.Removed[len(got.Removed)-1].Text != tt.text {
+				t.Errorf("Normalize() Removed text = %q, want %q", got.Removed[len(got.Removed)-1].Text, tt.text)
+			}
+		})
+	}
+}
+
+func TestParseWithOptions_Normalize(t *testing.T) {
+	raw := "]Q1" + normalizeTestPayload + "\r\n"
+	if _, err := Parse(raw, true, true); err == nil {
+		t.Error("Parse() should return error for scanner input")
+	}
+
+	qr, err := ParseWithOptions(raw, ParseOptions{VerifyCRC: true, SubTagDepth: 1, Normalize: true})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	if qr.GetPayload() != normalizeTestPayload {
+		t.Errorf("ParseWithOptions() GetPayload() = %v, want %v", qr.GetPayload(), normalizeTestPayload)
+	}
+}
+
+func TestParseBarcodeWithOptions_Normalize(t *testing.T) {
+	raw := "\uFEFF|099999999999990\r111222333444\r\r0\r\n"
+	barcode, err := ParseBarcodeWithOptions(raw, BarcodeParseOptions{Normalize: true})
+	if err != nil {
+		t.Fatalf("ParseBarcodeWithOptions() error = %v", err)
+	}
+	if barcode.BillerID != "099999999999990" {
+		t.Errorf("ParseBarcodeWithOptions() BillerID = %v, want 099999999999990", barcode.BillerID)
+	}
+}
@@ -4,10 +4,47 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/klimakov/thai-qr-go/biller"
 )
 
 var tlvPattern = regexp.MustCompile(`^\d{4}.+`)
 
+// ParseOptions configures ParseWithOptions.
+type ParseOptions struct {
+	// VerifyCRC validates the CRC checksum before parsing
+	VerifyCRC bool
+
+	// CRCTagID is the tag ID that must carry the checksum (e.g. "63" or "91").
+	// If empty, the last 4 characters are checked regardless of the tag ID.
+	CRCTagID string
+
+	// SubTagDepth is how many levels of nested TLV sub-tags to decode.
+	// 0 disables sub-tag decoding, 1 decodes sub-tags of root tags only.
+	SubTagDepth int
+
+	// LengthMode selects how TLV lengths are counted (default: bytes)
+	LengthMode LengthMode
+
+	// Normalize cleans up the payload with Normalize before parsing
+	Normalize bool
+
+	// MaxPayloadSize is the maximum accepted payload size in bytes (0: unlimited)
+	MaxPayloadSize int
+
+	// Billers is the biller directory that Bill Payment references are checked
+	// against (default: biller.Default). QR codes that break the biller's rules
+	// fail to parse, or are reported as errors in lenient mode.
+	Billers *biller.Directory
+
+	// Lenient keeps parsing past problems and reports all of them.
+	//
+	// In lenient mode ParseWithOptions always returns a best-effort EMVCoQR.
+	// If any errors were found, the error is a Diagnostics value listing them.
+	// EMVCoQR.Diagnostics lists them together with any warnings.
+	Lenient bool
+}
+
 // Parse parses an EMVCo-compatible QR code data string.
 //
 // Parameters:
@@ -17,63 +54,250 @@ var tlvPattern = regexp.MustCompile(`^\d{4}.+`)
 //
 // Returns an EMVCoQR instance with TLV tags, or an error if parsing fails.
 func Parse(payload string, strict, subTags bool) (*EMVCoQR, error) {
-	if !tlvPattern.MatchString(payload) {
-		return nil, fmt.Errorf("invalid QR code format: payload must start with 4 digits")
+	opts := ParseOptions{VerifyCRC: strict}
+	if subTags {
+		opts.SubTagDepth = 1
+	}
+	return ParseWithOptions(payload, opts)
+}
+
+// ParseWithOptions parses an EMVCo-compatible QR code data string using the given options.
+//
+// Returns an EMVCoQR instance with TLV tags, or an error if parsing fails.
+// See ParseOptions.Lenient for the lenient mode behaviour.
+func ParseWithOptions(payload string, opts ParseOptions) (*EMVCoQR, error) {
+	if opts.Normalize {
+		payload = Normalize(payload).Payload
+	}
+
+	p := &parser{opts: opts}
+	qr := p.parse(payload)
+
+	qr.diagnostics = p.diagnostics
+
+	if opts.Lenient {
+		if errs := p.diagnostics.Errors(); len(errs) > 0 {
+			return qr, errs
+		}
+		return qr, nil
+	}
+
+	if p.err != nil {
+		return nil, p.err
+	}
+	return qr, nil
+}
+
+// parser holds the state of a single ParseWithOptions call.
+type parser struct {
+	opts        ParseOptions
+	diagnostics Diagnostics
+	err         error
+}
+
+// fail records a problem. It returns true if parsing must stop,
+// which is the case for the first problem outside of lenient mode.
+func (p *parser) fail(offset int, path string, err error) bool {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Severity: SeverityError, Offset: offset, Path: path, Message: err.Error()})
+	if p.opts.Lenient {
+		return false
 	}
+	p.err = err
+	return true
+}
+
+// note records a problem that is only reported in lenient mode.
+func (p *parser) note(offset int, path, message string) {
+	if p.opts.Lenient {
+		p.diagnostics = append(p.diagnostics, Diagnostic{Severity: SeverityError, Offset: offset, Path: path, Message: message})
+	}
+}
+
+// warn records a suspicious value. Warnings are reported in every mode
+// and never make parsing fail.
+func (p *parser) warn(offset int, path, message string) {
+	p.diagnostics = append(p.diagnostics, Diagnostic{Severity: SeverityWarning, Offset: offset, Path: path, Message: message})
+}
 
-	if strict {
-		if len(payload) < 4 {
-			return nil, fmt.Errorf("invalid QR code format: payload too short for checksum validation")
+func (p *parser) parse(payload string) *EMVCoQR {
+	qr := &EMVCoQR{payload: payload}
+
+	if p.opts.MaxPayloadSize > 0 && len(payload) > p.opts.MaxPayloadSize {
+		err := fmt.Errorf("invalid QR code format: payload size %d exceeds limit of %d", len(payload), p.opts.MaxPayloadSize)
+		if p.fail(p.opts.MaxPayloadSize, "", err) {
+			return qr
 		}
-		expected := strings.ToUpper(payload[len(payload)-4:])
-		calculated := Checksum(payload[:len(payload)-4], true)
-		if expected != calculated {
-			return nil, fmt.Errorf("invalid CRC checksum: expected %s, got %s", expected, calculated)
+	}
+
+	if !tlvPattern.MatchString(payload) {
+		err := fmt.Errorf("invalid QR code format: payload must start with 4 digits")
+		if p.fail(0, "", err) {
+			return qr
 		}
 	}
 
-	tags, err := Decode(payload)
+	if p.opts.VerifyCRC && !p.verifyCRC(payload) {
+		return qr
+	}
+
+	tags, offset, err := decodeTLV(payload, p.opts.LengthMode)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decode TLV: %w", err)
+		if p.fail(offset, "", fmt.Errorf("failed to decode TLV: %w", err)) {
+			return qr
+		}
 	}
 
 	if len(tags) == 0 {
-		return nil, fmt.Errorf("no tags found in payload")
+		if p.fail(-1, "", fmt.Errorf("no tags found in payload")) {
+			return qr
+		}
 	}
 
-	if subTags {
-		for i := range tags {
-			if len(tags[i].Value) >= 4 && tlvPattern.MatchString(tags[i].Value) {
-				sub, err := Decode(tags[i].Value)
-				if err != nil {
-					continue
-				}
-
-				// Validate that all decoded sub-tags are valid (length matches value length)
-				valid := true
-				for _, subTag := range sub {
-					if subTag.Length != len(subTag.Value) {
-						valid = false
-						break
-					}
-				}
-
-				if valid && len(sub) > 0 {
-					tags[i].SubTags = sub
-				}
+	p.checkRootTags(tags)
+
+	if p.opts.SubTagDepth > 0 {
+		decodeSubTags(tags, p.opts.SubTagDepth, p.opts.LengthMode)
+		p.checkTemplates(tags)
+	}
+
+	p.lint(tags)
+
+	qr.tags = tags
+	return qr
+}
+
+// verifyCRC checks the payload checksum. It returns false if parsing must stop.
+func (p *parser) verifyCRC(payload string) bool {
+	if len(payload) < 4 {
+		err := fmt.Errorf("invalid QR code format: payload too short for checksum validation")
+		return !p.fail(0, "", err)
+	}
+
+	crcOffset := len(payload) - 4
+	if p.opts.CRCTagID != "" {
+		header := p.opts.CRCTagID + "04"
+		if len(payload) < 8 || payload[crcOffset-4:crcOffset] != header {
+			err := fmt.Errorf("invalid QR code format: payload must end with CRC tag %s", p.opts.CRCTagID)
+			if p.fail(max(crcOffset-4, 0), p.opts.CRCTagID, err) {
+				return false
 			}
 		}
 	}
 
-	return &EMVCoQR{
-		payload: payload,
-		tags:    tags,
-	}, nil
+	expected := strings.ToUpper(payload[crcOffset:])
+	calculated := Checksum(payload[:crcOffset], true)
+	if expected != calculated {
+		err := fmt.Errorf("invalid CRC checksum: expected %s, got %s", expected, calculated)
+		return !p.fail(crcOffset, p.opts.CRCTagID, err)
+	}
+
+	return true
+}
+
+// checkRootTags reports duplicated root tags in lenient mode.
+func (p *parser) checkRootTags(tags []TLVTag) {
+	seen := make(map[string]bool, len(tags))
+	offset := 0
+	for _, tag := range tags {
+		if seen[tag.ID] {
+			p.note(offset, tag.ID, "duplicate tag")
+		}
+		seen[tag.ID] = true
+		offset += 4 + len(tag.Value)
+	}
+}
+
+// checkTemplates reports template tags whose value is not valid TLV in lenient mode.
+func (p *parser) checkTemplates(tags []TLVTag) {
+	offset := 0
+	for _, tag := range tags {
+		if isTemplateTag(tag.ID) && len(tag.SubTags) == 0 && tag.Value != "" {
+			p.note(offset+4, tag.ID, "template value is not valid TLV")
+		}
+		offset += 4 + len(tag.Value)
+	}
+}
+
+// isTemplateTag reports whether a root tag ID is defined by EMVCo as a template
+// (merchant account information, additional data, or alternate language).
+func isTemplateTag(id string) bool {
+	return (id >= "26" && id <= "51") || id == "62" || id == "64"
+}
+
+// decodeSubTags decodes nested TLV values up to the given depth.
+func decodeSubTags(tags []TLVTag, depth int, mode LengthMode) {
+	for i := range tags {
+		if len(tags[i].Value) < 4 || !tlvPattern.MatchString(tags[i].Value) {
+			continue
+		}
+
+		sub, _, err := decodeTLV(tags[i].Value, mode)
+		if err != nil || len(sub) == 0 {
+			continue
+		}
+
+		if depth > 1 {
+			decodeSubTags(sub, depth-1, mode)
+		}
+		tags[i].SubTags = sub
+	}
 }
 
-// ParseBarcode parses a BOT Barcode data string.
+// ParseBarcode parses a BOT Barcode data string and checks it against
+// biller.Default.
 //
 // Returns a BOTBarcode instance, or an error if parsing fails.
 func ParseBarcode(payload string) (*BOTBarcode, error) {
-	return BOTBarcodeFromString(payload)
+	return ParseBarcodeWithOptions(payload, BarcodeParseOptions{})
+}
+
+// BarcodeParseOptions configures ParseBarcodeWithOptions.
+type BarcodeParseOptions struct {
+	// Normalize cleans up the payload with Normalize before parsing
+	Normalize bool
+
+	// Lenient accepts the variants sent by some scanners and keyboard wedges:
+	// LF or CR LF separators, a missing leading '|', trailing separators and
+	// Code 128 FNC characters. See BarcodeVariants for the variants of a payload.
+	Lenient bool
+
+	// Strict enforces the BOT field rules: a 15-digit biller ID, a numeric
+	// amount and references of 1-18 (Ref1) and 0-18 (Ref2) characters
+	Strict bool
+
+	// Billers is the biller directory the barcode is checked against
+	// (default: biller.Default). Barcodes that break the biller's rules are rejected.
+	Billers *biller.Directory
+}
+
+// ParseBarcodeWithOptions parses a BOT Barcode data string using the given options.
+//
+// Returns a BOTBarcode instance, or an error if parsing fails.
+func ParseBarcodeWithOptions(payload string, opts BarcodeParseOptions) (*BOTBarcode, error) {
+	if opts.Normalize {
+		payload = Normalize(payload).Payload
+	}
+
+	if opts.Lenient {
+		payload, _ = lenientBarcode(payload)
+	}
+
+	barcode, err := BOTBarcodeFromString(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Strict {
+		if err := checkBarcodeFields(barcode); err != nil {
+			return nil, fmt.Errorf("invalid BOT Barcode: %w", err)
+		}
+	}
+	directory := opts.Billers
+	if directory == nil {
+		directory = biller.Default
+	}
+	if err := directory.Validate(barcode.BillerID, barcode.Ref1, barcode.Ref2, barcode.Amount != nil); err != nil {
+		return nil, fmt.Errorf("invalid BOT Barcode: %w", err)
+	}
+	return barcode, nil
 }
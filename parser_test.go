@@ -1,6 +1,11 @@
 package thaiqrgo
 
-import "testing"
+import (
+	"errors"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go/biller"
+)
 
 func TestParse_InvalidString(t *testing.T) {
 	_, err := Parse("AAAA0000", false, true)
@@ -242,3 +247,250 @@ func TestEMVCoQR_GetTagValue(t *testing.T) {
 		t.Errorf("GetPayload() = %v, want 000411110104222202043333", payload)
 	}
 }
+
+func TestParseWithOptions_SubTagDepth(t *testing.T) {
+	// Tag 62 containing a payment system specific template (62.50) with its own sub-tags
+	payload := "00020101021129370016A0000006770101110113006680111111153037645802TH6212500800040001"
+
+	qr, err := ParseWithOptions(payload, ParseOptions{SubTagDepth: 0})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	if len(qr.GetTag("29", "").SubTags) != 0 {
+		t.Error("ParseWithOptions() with SubTagDepth=0 should not decode sub-tags")
+	}
+
+	qr, err = ParseWithOptions(payload, ParseOptions{SubTagDepth: 2})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	tag := qr.GetTag("62", "50")
+	if tag == nil || len(tag.SubTags) != 1 || tag.SubTags[0].Value != "0001" {
+		t.Errorf("ParseWithOptions() with SubTagDepth=2 Tag 62.50 = %+v, want one sub-tag with value 0001", tag)
+	}
+}
+
+func TestParseWithOptions_CRCTagID(t *testing.T) {
+	payload := "00020101021229370016A0000006770101110113006680111111153037645802TH540520.15630442BE"
+	if _, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, CRCTagID: "63"}); err != nil {
+		t.Errorf("ParseWithOptions() with CRCTagID=63 error = %v", err)
+	}
+	if _, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, CRCTagID: "91"}); err == nil {
+		t.Error("ParseWithOptions() with CRCTagID=91 should return error")
+	}
+}
+
+func TestParseWithOptions_MaxPayloadSize(t *testing.T) {
+	payload := "00020101021229370016A0000006770101110113006680111111153037645802TH540520.15630442BE"
+	if _, err := ParseWithOptions(payload, ParseOptions{MaxPayloadSize: 20}); err == nil {
+		t.Error("ParseWithOptions() should return error for payload exceeding MaxPayloadSize")
+	}
+	if _, err := ParseWithOptions(payload, ParseOptions{MaxPayloadSize: 512}); err != nil {
+		t.Errorf("ParseWithOptions() with MaxPayloadSize=512 error = %v", err)
+	}
+}
+
+func TestParseWithOptions_LengthRunes(t *testing.T) {
+	// Tag 59 holds a 3-character Thai name (9 bytes in UTF-8)
+	payload := "000201" + "5903ไทย" + "5802TH"
+
+	if _, err := ParseWithOptions(payload, ParseOptions{}); err == nil {
+		t.Error("ParseWithOptions() with byte lengths should fail on a Thai name")
+	}
+
+	qr, err := ParseWithOptions(payload, ParseOptions{LengthMode: LengthRunes})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() with LengthRunes error = %v", err)
+	}
+	if got := qr.GetTagValue("59", ""); got != "ไทย" {
+		t.Errorf("ParseWithOptions() Tag 59 = %v, want ไทย", got)
+	}
+	if got := qr.GetTagValue("58", ""); got != "TH" {
+		t.Errorf("ParseWithOptions() Tag 58 = %v, want TH", got)
+	}
+}
+
+func TestParseWithOptions_Lenient(t *testing.T) {
+	// Bad checksum, duplicate Tag 53, malformed Tag 30 template and a truncated trailing tag
+	payload := "000201010211300512345530376453037645802TH63041234" + "5910ABC"
+
+	if _, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, SubTagDepth: 1}); err == nil {
+		t.Fatal("ParseWithOptions() without lenient mode should return error")
+	}
+
+	qr, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, SubTagDepth: 1, Lenient: true})
+	if qr == nil {
+		t.Fatal("ParseWithOptions() in lenient mode should return a QR")
+	}
+	diags, ok := err.(Diagnostics)
+	if !ok {
+		t.Fatalf("ParseWithOptions() error = %T, want Diagnostics", err)
+	}
+	if len(diags) != 4 {
+		t.Errorf("ParseWithOptions() diagnostics = %v, want 4", diags)
+	}
+	if len(qr.Diagnostics()) != len(diags) {
+		t.Errorf("EMVCoQR.Diagnostics() = %d entries, want %d", len(qr.Diagnostics()), len(diags))
+	}
+	if got := qr.GetTagValue("58", ""); got != "TH" {
+		t.Errorf("ParseWithOptions() Tag 58 = %v, want TH", got)
+	}
+
+	paths := map[string]bool{}
+	for _, d := range diags {
+		paths[d.Path] = true
+	}
+	for _, path := range []string{"30", "53"} {
+		if !paths[path] {
+			t.Errorf("ParseWithOptions() diagnostics missing Tag %s: %v", path, diags)
+		}
+	}
+}
+
+func TestParseWithOptions_LenientClean(t *testing.T) {
+	payload := "00020101021229370016A0000006770101110113006680111111153037645802TH540520.15630442BE"
+	qr, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, SubTagDepth: 1, Lenient: true})
+	if err != nil {
+		t.Fatalf("ParseWithOptions() error = %v", err)
+	}
+	if len(qr.Diagnostics()) != 0 {
+		t.Errorf("EMVCoQR.Diagnostics() = %v, want none", qr.Diagnostics())
+	}
+}
+
+func TestParse_LintWarnings(t *testing.T) {
+	// NATID '1111111111111' has a wrong check digit, but is still a valid QR
+	payload := "00020101021129370016A000000677010111021311111111111115802TH530376463047B5A"
+	qr, err := Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	warnings := qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "29.02" || warnings[0].Offset != 40 {
+		t.Errorf("Parse() warnings = %v, want one for Tag 29.02 at position 40", warnings)
+	}
+
+	// Warnings do not make lenient parsing fail
+	if _, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, Lenient: true}); err != nil {
+		t.Errorf("ParseWithOptions() in lenient mode error = %v, want nil", err)
+	}
+
+	// Biller ID with a wrong tax ID check digit
+	qr, err = Parse("00020101021130550016A0000006770101120115099999999999990021211122233344453037645802TH63043EE7", true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	warnings = qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "30.01" {
+		t.Errorf("Parse() warnings = %v, want one for Tag 30.01", warnings)
+	}
+
+	// MSISDN proxy with a landline prefix
+	qr, err = Parse("00020101021129370016A0000006770101110113006621222333353037645802TH63040000", false, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	warnings = qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "29.01" {
+		t.Errorf("Parse() warnings = %v, want one for Tag 29.01", warnings)
+	}
+
+	// BANKACC proxy with an unknown bank code
+	qr, err = Parse("00020101021129370016A0000006770101110413999123456789053037645802TH63040000", false, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	warnings = qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "29.04" {
+		t.Errorf("Parse() warnings = %v, want one for Tag 29.04", warnings)
+	}
+
+	// Malformed EWALLETID proxy
+	qr, err = Parse("00020101021129380016A000000677010111031401234567890123530376463040000", false, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	warnings = qr.Diagnostics().Warnings()
+	if len(warnings) != 1 || warnings[0].Path != "29.03" {
+		t.Errorf("Parse() warnings = %v, want one for Tag 29.03", warnings)
+	}
+
+	// EWALLETID proxy of a provider missing from the registry
+	qr, err = Parse("00020101021129390016A0000006770101110315012345678901234530376463040000", false, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if warnings := qr.Diagnostics().Warnings(); len(warnings) != 0 {
+		t.Errorf("Parse() warnings = %v, want none", warnings)
+	}
+
+	// Valid IDs produce no warnings
+	qr, err = Parse("00020101021230650016A00000067701011201150994000165501000212123456789012030667042953037645802TH54073649.2263044534", true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(qr.Diagnostics()) != 0 {
+		t.Errorf("Parse() diagnostics = %v, want none", qr.Diagnostics())
+	}
+}
+
+func TestParse_BillerDirectory(t *testing.T) {
+	directory, err := biller.NewDirectory(biller.Biller{
+		TaxID:  "0994000165501",
+		Suffix: "00",
+		Name:   "Test Utility",
+		Ref1:   biller.Rule{MaxLength: 10, Charset: biller.CharsetNumeric},
+	})
+	if err != nil {
+		t.Fatalf("NewDirectory() error = %v", err)
+	}
+
+	// Ref1 "123456789012" is longer than the biller allows
+	payload := "00020101021230650016A00000067701011201150994000165501000212123456789012030667042953037645802TH54073649.2263044534"
+	var ruleErr *biller.RuleError
+	if _, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, SubTagDepth: 1, Billers: directory}); !errors.As(err, &ruleErr) || ruleErr.Field != "Ref1" {
+		t.Errorf("ParseWithOptions() error = %v, want Ref1 rule error", err)
+	}
+	qr, err := ParseWithOptions(payload, ParseOptions{VerifyCRC: true, SubTagDepth: 1, Billers: directory, Lenient: true})
+	if errs := qr.Diagnostics().Errors(); err == nil || len(errs) != 1 || errs[0].Path != "30.02" {
+		t.Errorf("ParseWithOptions() lenient diagnostics = %v, want one error for Tag 30.02", qr.Diagnostics())
+	}
+
+	// The default directory is empty, so nothing is reported
+	qr, err = Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(qr.Diagnostics()) != 0 {
+		t.Errorf("Parse() diagnostics = %v, want none", qr.Diagnostics())
+	}
+
+	barcode := "|099400016550100\r123456789012\r670429\r364922"
+	if _, err := ParseBarcodeWithOptions(barcode, BarcodeParseOptions{Billers: directory}); !errors.As(err, &ruleErr) || ruleErr.Field != "Ref1" {
+		t.Errorf("ParseBarcodeWithOptions() error = %v, want Ref1 rule error", err)
+	}
+	if _, err := ParseBarcodeWithOptions(barcode, BarcodeParseOptions{}); err != nil {
+		t.Errorf("ParseBarcodeWithOptions() with the default directory error = %v", err)
+	}
+	if _, err := ParseBarcodeWithOptions("|099400016550100\r1234567890\r\r0", BarcodeParseOptions{Billers: directory}); err != nil {
+		t.Errorf("ParseBarcodeWithOptions() error = %v", err)
+	}
+}
+
+func TestParseBarcode_DefaultBillers(t *testing.T) {
+	directory, err := biller.NewDirectory(biller.Biller{TaxID: "0994000165501", Suffix: "00", Name: "Test Utility", AmountRequired: true})
+	if err != nil {
+		t.Fatalf("NewDirectory() error = %v", err)
+	}
+	saved := biller.Default
+	biller.Default = directory
+	defer func() { biller.Default = saved }()
+
+	var ruleErr *biller.RuleError
+	if _, err := ParseBarcode("|099400016550100\r123\r\r0"); !errors.As(err, &ruleErr) || ruleErr.Field != "Amount" {
+		t.Errorf("ParseBarcode() error = %v, want Amount rule error", err)
+	}
+	if _, err := ParseBarcode("|099400016550100\r123\r\r10000"); err != nil {
+		t.Errorf("ParseBarcode() error = %v", err)
+	}
+}
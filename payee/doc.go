@@ -0,0 +1,11 @@
+// Package payee checks that a scanned QR code pays one of a merchant's
+// registered payees.
+//
+// A common scam is to stick a fake PromptPay QR code over a merchant's real one.
+// An Allowlist holds the merchant's PromptPay proxies, biller IDs, e-wallet IDs
+// and card scheme merchant IDs; Allowlist.Verify compares every merchant account
+// of a QR code against it and reports a match, a mismatch or an unknown payee,
+// with reasons. It also flags look-alike tricks in the merchant name, such as
+// control characters, non-ASCII letters in the ASCII-only Tag 59, or a local
+// name (Tag 64.01) mixing letters of scripts that look alike.
+package payee
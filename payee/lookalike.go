@@ -0,0 +1,77 @@
+package payee
+
+import (
+	"fmt"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// confusableScripts are scripts with letters that look like Latin letters,
+// e.g. Cyrillic "а" (U+0430) and Latin "a". Thai is not among them, so names
+// mixing Thai and Latin are not flagged.
+var confusableScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Latin", unicode.Latin},
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Armenian", unicode.Armenian},
+}
+
+// lookalikes reports characters in a name that can make it look like
+// another name: control and invisible formatting characters (e.g. zero-width
+// spaces or right-to-left overrides), fullwidth forms, and letters of several
+// scripts that look alike.
+//
+// asciiOnly is set for fields limited to ASCII, such as the merchant name
+// (Tag 59). Any non-ASCII letter is flagged there, so that names written
+// entirely in look-alike letters of another script (e.g. Cyrillic "ТОР") are
+// caught too.
+func lookalikes(path, name string, asciiOnly bool) []Reason {
+	var flags []Reason
+	flag := func(message string) {
+		flags = append(flags, Reason{Status: StatusUnknown, Path: path, Message: message})
+	}
+
+	if !utf8.ValidString(name) {
+		flag("name is not valid UTF-8")
+		return flags
+	}
+
+	var control, fullwidth, nonASCII rune = -1, -1, -1
+	var scripts []string
+	for _, r := range name {
+		switch {
+		case control < 0 && (unicode.IsControl(r) || unicode.Is(unicode.Cf, r)):
+			control = r
+		case fullwidth < 0 && r >= 0xff01 && r <= 0xff5e:
+			fullwidth = r
+		case asciiOnly && unicode.IsLetter(r):
+			if nonASCII < 0 && r > unicode.MaxASCII {
+				nonASCII = r
+			}
+		case unicode.IsLetter(r):
+			for _, script := range confusableScripts {
+				if unicode.Is(script.table, r) && !slices.Contains(scripts, script.name) {
+					scripts = append(scripts, script.name)
+				}
+			}
+		}
+	}
+
+	if control >= 0 {
+		flag(fmt.Sprintf("name contains control or invisible character %U", control))
+	}
+	if fullwidth >= 0 {
+		flag(fmt.Sprintf("name contains fullwidth character %U that looks like ASCII", fullwidth))
+	}
+	if nonASCII >= 0 {
+		flag(fmt.Sprintf("name contains non-ASCII letter %U in an ASCII-only field", nonASCII))
+	}
+	if len(scripts) > 1 {
+		flag(fmt.Sprintf("name mixes look-alike letters of scripts %v", scripts))
+	}
+	return flags
+}
@@ -0,0 +1,251 @@
+package payee
+
+import (
+	"strings"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/aid"
+	"github.com/klimakov/thai-qr-go/generate"
+	"github.com/klimakov/thai-qr-go/phone"
+)
+
+// Status is the outcome of a payee check.
+type Status int
+
+// Payee check outcomes.
+const (
+	// StatusUnknown means the QR code has a payee that cannot be checked,
+	// e.g. a scheme the allowlist has no entries for, or no payee at all
+	StatusUnknown Status = iota
+
+	// StatusMatch means every payee of the QR code is in the allowlist
+	StatusMatch
+
+	// StatusMismatch means the QR code has a payee that is not in the allowlist
+	StatusMismatch
+)
+
+// String implements the fmt.Stringer interface.
+func (s Status) String() string {
+	switch s {
+	case StatusMatch:
+		return "match"
+	case StatusMismatch:
+		return "mismatch"
+	}
+	return "unknown"
+}
+
+// Allowlist holds the registered payees of a merchant.
+type Allowlist struct {
+	// Proxies are PromptPay AnyID proxies. A proxy only matches proxies of its type.
+	Proxies []Proxy
+
+	// BillerIDs are PromptPay Bill Payment biller IDs (Tag 30.01)
+	BillerIDs []string
+
+	// WalletIDs are 15-digit e-wallet IDs (Tag 29.03)
+	WalletIDs []string
+
+	// MerchantIDs are card scheme merchant IDs (Tags 02-25) and cross-border
+	// merchant IDs (Tag 30.02)
+	MerchantIDs []string
+}
+
+// Proxy is a PromptPay AnyID proxy in an allowlist.
+type Proxy struct {
+	// Type is the proxy type, the sub-tag of Tag 29 holding it:
+	// generate.ProxyTypeMSISDN, generate.ProxyTypeNATID or generate.ProxyTypeBANKACC
+	Type string
+
+	// Value is a mobile number (in any form accepted by phone.Parse), a national
+	// ID or tax ID (13 digits), or a bank account proxy (3-digit bank code
+	// followed by the account number)
+	Value string
+}
+
+// Reason explains the outcome for one field of the QR code.
+type Reason struct {
+	// Status is the outcome for the field
+	Status Status
+
+	// Path is the tag path, e.g. "29.01" or "59"
+	Path string
+
+	// Message describes the outcome
+	Message string
+}
+
+// String implements the fmt.Stringer interface.
+func (r Reason) String() string {
+	return r.Path + ": " + r.Message
+}
+
+// Result is the outcome of Allowlist.Verify.
+type Result struct {
+	// Status is StatusMismatch if any payee is not in the allowlist,
+	// StatusUnknown if any payee cannot be checked, and StatusMatch otherwise
+	Status Status
+
+	// Reasons explain the outcome for each payee, in tag order
+	Reasons []Reason
+
+	// Flags are look-alike tricks found in the merchant name (Tags 59 and 64.01).
+	// They do not change Status, but a QR code with flags should not be trusted
+	// on the strength of its name.
+	Flags []Reason
+}
+
+// Verify checks every merchant account of the QR code against the allowlist.
+//
+// A QR code may carry several payees, e.g. PromptPay AnyID and a card scheme;
+// the payer's app chooses one, so all of them must be in the allowlist.
+// Returns StatusUnknown with a reason if the QR code is not a merchant-presented
+// QR code or has no merchant account.
+func (a *Allowlist) Verify(q *thaiqrgo.EMVCoQR) *Result {
+	result := &Result{}
+	if q.GetTagValue("00", "") != "01" {
+		result.add(StatusUnknown, "00", "QR code is not a merchant-presented QR code")
+		result.Status = StatusUnknown
+		return result
+	}
+	for _, tag := range q.GetTags() {
+		if len(tag.ID) != 2 || tag.ID < "02" || tag.ID > "51" {
+			continue
+		}
+		if tag.ID <= "25" {
+			result.check(tag.ID, "card scheme merchant ID", tag.Value, a.MerchantIDs, cleanID)
+			continue
+		}
+
+		sub := subTags(tag)
+		switch guid := value(sub, "00"); guid {
+		case aid.PromptPayAnyID:
+			a.verifyAnyID(result, tag.ID, sub)
+		case aid.PromptPayBillPayment:
+			result.check(tag.ID+".01", "biller ID", value(sub, "01"), a.BillerIDs, cleanID)
+		case aid.PromptPayCrossBorder:
+			result.check(tag.ID+".02", "cross-border merchant ID", value(sub, "02"), a.MerchantIDs, cleanID)
+		default:
+			name := "with AID " + guid
+			if s, ok := aid.Lookup(guid); ok {
+				name = s.Name
+			} else if guid == "" {
+				name = "without AID"
+			}
+			result.add(StatusUnknown, tag.ID, "payee of scheme "+name+" cannot be checked")
+		}
+	}
+	if len(result.Reasons) == 0 {
+		result.add(StatusUnknown, "", "QR code has no merchant account")
+	}
+
+	result.Status = StatusMatch
+	for _, reason := range result.Reasons {
+		switch {
+		case reason.Status == StatusMismatch:
+			result.Status = StatusMismatch
+		case reason.Status == StatusUnknown && result.Status == StatusMatch:
+			result.Status = StatusUnknown
+		}
+	}
+
+	result.Flags = lookalikes("59", q.GetTagValue("59", ""), true)
+	if tag := q.GetTag("64", ""); tag != nil {
+		result.Flags = append(result.Flags, lookalikes("64.01", value(subTags(*tag), "01"), false)...)
+	}
+	return result
+}
+
+// verifyAnyID checks the proxies of a PromptPay AnyID template.
+func (a *Allowlist) verifyAnyID(result *Result, tagID string, sub []thaiqrgo.TLVTag) {
+	found := false
+	for _, proxy := range sub {
+		path := tagID + "." + proxy.ID
+		switch proxy.ID {
+		case generate.ProxyTypeMSISDN:
+			result.check(path, "MSISDN proxy", proxy.Value, a.proxies(proxy.ID), phoneProxy)
+		case generate.ProxyTypeNATID:
+			result.check(path, "NATID proxy", proxy.Value, a.proxies(proxy.ID), cleanID)
+		case generate.ProxyTypeEWALLETID:
+			result.check(path, "EWALLETID proxy", proxy.Value, a.WalletIDs, cleanID)
+		case generate.ProxyTypeBANKACC:
+			result.check(path, "BANKACC proxy", proxy.Value, a.proxies(proxy.ID), cleanID)
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		result.add(StatusUnknown, tagID, "PromptPay AnyID template has no proxy")
+	}
+}
+
+// proxies returns the values of the allowlisted proxies of a type.
+func (a *Allowlist) proxies(proxyType string) []string {
+	var values []string
+	for _, proxy := range a.Proxies {
+		if proxy.Type == proxyType {
+			values = append(values, proxy.Value)
+		}
+	}
+	return values
+}
+
+// check records whether a payee value is in the allowlist entries,
+// comparing both in the form returned by normalize.
+func (r *Result) check(path, kind, payee string, entries []string, normalize func(string) string) {
+	if payee == "" {
+		r.add(StatusUnknown, path, kind+" is missing")
+		return
+	}
+	if len(entries) == 0 {
+		r.add(StatusUnknown, path, kind+" "+payee+" cannot be checked: allowlist has no entries of this kind")
+		return
+	}
+	want := normalize(payee)
+	for _, entry := range entries {
+		if normalize(entry) == want {
+			r.add(StatusMatch, path, kind+" "+payee+" is registered")
+			return
+		}
+	}
+	r.add(StatusMismatch, path, kind+" "+payee+" is not registered")
+}
+
+// add records a reason.
+func (r *Result) add(status Status, path, message string) {
+	r.Reasons = append(r.Reasons, Reason{Status: status, Path: path, Message: message})
+}
+
+// phoneProxy returns a mobile number in proxy form, or the cleaned value
+// if it is not a Thai mobile number.
+func phoneProxy(s string) string {
+	number, err := phone.Parse(s)
+	if err != nil {
+		return cleanID(s)
+	}
+	return number.Proxy()
+}
+
+// cleanID removes spaces and dashes from an ID.
+func cleanID(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(s))
+}
+
+// subTags returns the sub-tags of a template tag, decoding them if needed.
+func subTags(tag thaiqrgo.TLVTag) []thaiqrgo.TLVTag {
+	if len(tag.SubTags) > 0 {
+		return tag.SubTags
+	}
+	sub, _ := thaiqrgo.Decode(tag.Value)
+	return sub
+}
+
+// value returns the value of the first tag with the given ID.
+func value(tags []thaiqrgo.TLVTag, id string) string {
+	if tag := thaiqrgo.Get(tags, id, ""); tag != nil {
+		return tag.Value
+	}
+	return ""
+}
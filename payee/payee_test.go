@@ -0,0 +1,162 @@
+package payee
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/aid"
+	"github.com/klimakov/thai-qr-go/generate"
+)
+
+func mustParse(t *testing.T, payload string, err error) *thaiqrgo.EMVCoQR {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+	qr, err := thaiqrgo.Parse(payload, true, false)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", payload, err)
+	}
+	return qr
+}
+
+func anyID(t *testing.T, config generate.AnyIDConfig) *thaiqrgo.EMVCoQR {
+	t.Helper()
+	payload, err := generate.AnyID(config)
+	return mustParse(t, payload, err)
+}
+
+func TestAllowlist_Verify(t *testing.T) {
+	allowlist := &Allowlist{
+		Proxies: []Proxy{
+			{generate.ProxyTypeMSISDN, "081-234-5678"},
+			{generate.ProxyTypeNATID, "1111111111119"},
+			{generate.ProxyTypeBANKACC, "004 1234567890"},
+		},
+		BillerIDs: []string{"099400016550100"},
+		WalletIDs: []string{"140000812345678"},
+	}
+
+	billPayment, err := generate.BillPayment(generate.BillPaymentConfig{BillerID: "099400016550100", Ref1: "123"})
+	otherBiller, otherErr := generate.BillPayment(generate.BillPaymentConfig{BillerID: "099999999901000", Ref1: "123"})
+	visa, visaErr := generate.MultiScheme(generate.MultiSchemeConfig{Accounts: []generate.SchemeAccount{
+		{AID: aid.PromptPayAnyID, Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag("01", "0066812345678")}},
+		{AID: aid.Visa, MerchantID: "4111111111111111"},
+	}})
+	unknownScheme, unknownErr := (&generate.Merchant{Accounts: []generate.MerchantAccount{
+		{ID: "31", GUID: "A000000999", Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag("01", "X")}},
+	}}).Build()
+	noAccount := thaiqrgo.WithCRCTag(thaiqrgo.Encode([]thaiqrgo.TLVTag{thaiqrgo.Tag("00", "01"), thaiqrgo.Tag("53", "764")}), "63", true)
+	slip, slipErr := generate.SlipVerify(generate.SlipVerifyConfig{SendingBank: "002", TransRef: "0002123123121200011"})
+
+	tests := []struct {
+		name      string
+		qr        *thaiqrgo.EMVCoQR
+		allowlist *Allowlist
+		want      Status
+		reason    string
+	}{
+		{"MSISDN", anyID(t, generate.AnyIDConfig{Type: "MSISDN", Target: "0812345678"}), allowlist, StatusMatch, "29.01: MSISDN proxy 0066812345678 is registered"},
+		{"other MSISDN", anyID(t, generate.AnyIDConfig{Type: "MSISDN", Target: "0899999999"}), allowlist, StatusMismatch, "29.01: MSISDN proxy 0066899999999 is not registered"},
+		{"NATID", anyID(t, generate.AnyIDConfig{Type: "NATID", Target: "1111111111119"}), allowlist, StatusMatch, "29.02: NATID proxy 1111111111119 is registered"},
+		{"BANKACC", anyID(t, generate.AnyIDConfig{Type: "BANKACC", BankCode: "004", Target: "1234567890"}), allowlist, StatusMatch, "29.04: BANKACC proxy 0041234567890 is registered"},
+		{"BANKACC equal to a national ID", anyID(t, generate.AnyIDConfig{Type: "BANKACC", BankCode: "004", Target: "1234567890"}), &Allowlist{Proxies: []Proxy{{generate.ProxyTypeNATID, "0041234567890"}}}, StatusUnknown, "29.04: BANKACC proxy 0041234567890 cannot be checked: allowlist has no entries of this kind"},
+		{"NATID equal to a bank account", anyID(t, generate.AnyIDConfig{Type: "NATID", Target: "0041234567890"}), &Allowlist{Proxies: []Proxy{{generate.ProxyTypeBANKACC, "0041234567890"}}}, StatusUnknown, "29.02: NATID proxy 0041234567890 cannot be checked: allowlist has no entries of this kind"},
+		{"EWALLETID", anyID(t, generate.AnyIDConfig{Type: "EWALLETID", WalletCode: "140", Target: "0812345678"}), allowlist, StatusMatch, "29.03: EWALLETID proxy 140000812345678 is registered"},
+		{"EWALLETID as proxy", anyID(t, generate.AnyIDConfig{Type: "EWALLETID", WalletCode: "140", Target: "0812345678"}), &Allowlist{WalletIDs: []string{"140000899999999"}}, StatusMismatch, "29.03: EWALLETID proxy 140000812345678 is not registered"},
+		{"biller", mustParse(t, billPayment, err), allowlist, StatusMatch, "30.01: biller ID 099400016550100 is registered"},
+		{"other biller", mustParse(t, otherBiller, otherErr), allowlist, StatusMismatch, "30.01: biller ID 099999999901000 is not registered"},
+		{"no biller IDs", mustParse(t, billPayment, err), &Allowlist{Proxies: allowlist.Proxies}, StatusUnknown, "30.01: biller ID 099400016550100 cannot be checked: allowlist has no entries of this kind"},
+		{"card scheme not listed", mustParse(t, visa, visaErr), allowlist, StatusUnknown, "02: card scheme merchant ID 4111111111111111 cannot be checked: allowlist has no entries of this kind"},
+		{"card scheme listed", mustParse(t, visa, visaErr), &Allowlist{Proxies: allowlist.Proxies, MerchantIDs: []string{"4111111111111111"}}, StatusMatch, "02: card scheme merchant ID 4111111111111111 is registered"},
+		{"unknown scheme", mustParse(t, unknownScheme, unknownErr), allowlist, StatusUnknown, "31: payee of scheme with AID A000000999 cannot be checked"},
+		{"no merchant account", mustParse(t, noAccount, nil), allowlist, StatusUnknown, ": QR code has no merchant account"},
+		{"slip verify", mustParse(t, slip, slipErr), allowlist, StatusUnknown, "00: QR code is not a merchant-presented QR code"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.allowlist.Verify(tt.qr)
+			if got.Status != tt.want {
+				t.Errorf("Verify() Status = %v, want %v (reasons %v)", got.Status, tt.want, got.Reasons)
+			}
+			var reasons []string
+			for _, reason := range got.Reasons {
+				reasons = append(reasons, reason.String())
+			}
+			if !strings.Contains(strings.Join(reasons, "\n"), tt.reason) {
+				t.Errorf("Verify() Reasons = %v, want %q", reasons, tt.reason)
+			}
+		})
+	}
+}
+
+func TestAllowlist_VerifyMismatchWins(t *testing.T) {
+	payload, err := generate.MultiScheme(generate.MultiSchemeConfig{Accounts: []generate.SchemeAccount{
+		{AID: aid.PromptPayAnyID, Fields: []thaiqrgo.TLVTag{thaiqrgo.Tag("01", "0066899999999")}},
+		{AID: aid.Visa, MerchantID: "4111111111111111"},
+	}})
+	got := (&Allowlist{Proxies: []Proxy{{generate.ProxyTypeMSISDN, "0812345678"}}}).Verify(mustParse(t, payload, err))
+	if got.Status != StatusMismatch {
+		t.Errorf("Verify() Status = %v, want %v", got.Status, StatusMismatch)
+	}
+}
+
+func TestAllowlist_VerifyFlags(t *testing.T) {
+	allowlist := &Allowlist{Proxies: []Proxy{{generate.ProxyTypeMSISDN, "0812345678"}}}
+
+	tests := []struct {
+		name  string
+		shop  string
+		local string
+		flags []string
+	}{
+		{"plain", "MY CAFE", "", nil},
+		{"Cyrillic E", "MY CAFЕ", "", []string{"59: name contains non-ASCII letter U+0415 in an ASCII-only field"}},
+		{"all Cyrillic", "ТОР", "", []string{"59: name contains non-ASCII letter U+0422 in an ASCII-only field"}},
+		{"zero-width space", "MY​CAFE", "", []string{"59: name contains control or invisible character U+200B"}},
+		{"right-to-left override", "MY CAFE‮", "", []string{"59: name contains control or invisible character U+202E"}},
+		{"fullwidth", "ＭY CAFE", "", []string{"59: name contains fullwidth character U+FF2D that looks like ASCII"}},
+		{"local Thai and Latin", "MY CAFE", "ร้าน MY CAFE", nil},
+		{"local all Cyrillic", "MY CAFE", "Кафе", nil},
+		{"local Cyrillic E", "MY CAFE", "ร้าน MY CAFЕ", []string{"64.01: name mixes look-alike letters of scripts [Latin Cyrillic]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merchant := &generate.Merchant{Name: tt.shop}
+			merchant.AddAccount("29", aid.PromptPayAnyID, thaiqrgo.Tag("01", "0066812345678"))
+			if tt.local != "" {
+				merchant.LanguageTemplate = []thaiqrgo.TLVTag{thaiqrgo.Tag("00", "TH"), thaiqrgo.Tag("01", tt.local)}
+			}
+			payload, err := merchant.Build()
+			got := allowlist.Verify(mustParse(t, payload, err))
+
+			if got.Status != StatusMatch {
+				t.Errorf("Verify() Status = %v, want %v", got.Status, StatusMatch)
+			}
+			var flags []string
+			for _, flag := range got.Flags {
+				flags = append(flags, flag.String())
+			}
+			if strings.Join(flags, "\n") != strings.Join(tt.flags, "\n") {
+				t.Errorf("Verify() Flags = %v, want %v", flags, tt.flags)
+			}
+		})
+	}
+}
+
+func TestStatus_String(t *testing.T) {
+	tests := []struct {
+		status Status
+		want   string
+	}{
+		{StatusUnknown, "unknown"},
+		{StatusMatch, "match"},
+		{StatusMismatch, "mismatch"},
+	}
+	for _, tt := range tests {
+		if got := tt.status.String(); got != tt.want {
+			t.Errorf("String() = %v, want %v", got, tt.want)
+		}
+	}
+}
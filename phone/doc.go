@@ -0,0 +1,7 @@
+// Package phone parses and formats Thai mobile numbers.
+//
+// Numbers are accepted in local ("081-234-5678"), E.164 ("+66 81 234 5678")
+// and PromptPay proxy ("0066812345678") form. A parsed Number converts to
+// each of these forms, so the same value can be used to generate an MSISDN
+// proxy (Tag 29.01) and to display it back.
+package phone
@@ -0,0 +1,145 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+// CountryCode is the Thai country calling code.
+const CountryCode = "66"
+
+// subscriberLength is the number of digits after the trunk prefix 0
+// (or the country code) of a Thai mobile number.
+const subscriberLength = 9
+
+// ProxyLength is the length of a PromptPay MSISDN proxy.
+const ProxyLength = 13
+
+// Parsing errors, usable with errors.Is.
+var (
+	ErrFormat = errors.New("invalid phone number: unexpected characters")
+	ErrLength = errors.New("invalid phone number: must be 10 digits (e.g. 0812345678)")
+	ErrPrefix = errors.New("invalid phone number: not a Thai mobile prefix (06, 08 or 09)")
+)
+
+// Number is a Thai mobile number.
+//
+// It stores the 9 subscriber digits without the trunk prefix or country code.
+// The zero value is not a valid number; create one with Parse or FromProxy.
+type Number struct {
+	subscriber string
+}
+
+// Parse parses a Thai mobile number in local, E.164 or proxy form.
+//
+// Spaces, dashes, dots and parentheses are ignored, so "+66 (0)81-234-5678"
+// and "081.234.5678" are accepted.
+// Returns an error if the number is not a 10-digit Thai mobile number.
+func Parse(s string) (Number, error) {
+	var digits strings.Builder
+	plus := false
+	for i, c := range strings.TrimSpace(s) {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c == '+' && i == 0:
+			plus = true
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return Number{}, ErrFormat
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case plus:
+		if !strings.HasPrefix(number, CountryCode) {
+			return Number{}, ErrPrefix
+		}
+		number = strings.TrimPrefix(number[len(CountryCode):], "0")
+	case len(number) == ProxyLength && strings.HasPrefix(number, "00"+CountryCode):
+		number = number[2+len(CountryCode):]
+	case len(number) == len(CountryCode)+subscriberLength && strings.HasPrefix(number, CountryCode):
+		number = number[len(CountryCode):]
+	case len(number) == subscriberLength+1 && number[0] == '0':
+		number = number[1:]
+	default:
+		return Number{}, ErrLength
+	}
+
+	return newNumber(number)
+}
+
+// FromProxy converts a 13-digit PromptPay MSISDN proxy (e.g. "0066812345678") to a Number.
+func FromProxy(proxy string) (Number, error) {
+	if len(proxy) != ProxyLength || !strings.HasPrefix(proxy, "00"+CountryCode) {
+		return Number{}, ErrLength
+	}
+	return newNumber(proxy[2+len(CountryCode):])
+}
+
+// MustParse is like Parse but panics if the number cannot be parsed.
+func MustParse(s string) Number {
+	n, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func newNumber(subscriber string) (Number, error) {
+	if len(subscriber) != subscriberLength {
+		return Number{}, ErrLength
+	}
+	for i := 0; i < len(subscriber); i++ {
+		if subscriber[i] < '0' || subscriber[i] > '9' {
+			return Number{}, ErrFormat
+		}
+	}
+	switch subscriber[0] {
+	case '6', '8', '9':
+		return Number{subscriber: subscriber}, nil
+	}
+	return Number{}, ErrPrefix
+}
+
+// IsZero reports whether n is the zero value.
+func (n Number) IsZero() bool {
+	return n.subscriber == ""
+}
+
+// Local returns the number in local form (e.g. "0812345678").
+func (n Number) Local() string {
+	return "0" + n.subscriber
+}
+
+// E164 returns the number in E.164 form (e.g. "+66812345678").
+func (n Number) E164() string {
+	return "+" + CountryCode + n.subscriber
+}
+
+// Proxy returns the 13-digit PromptPay MSISDN proxy (e.g. "0066812345678").
+func (n Number) Proxy() string {
+	return "00" + CountryCode + n.subscriber
+}
+
+// Format returns the number in local form with dashes for display (e.g. "081-234-5678").
+//
+// Returns an empty string for the zero Number.
+func (n Number) Format() string {
+	if n.IsZero() {
+		return ""
+	}
+	local := n.Local()
+	return local[:3] + "-" + local[3:6] + "-" + local[6:]
+}
+
+// String implements the fmt.Stringer interface.
+//
+// Returns the number in local form.
+func (n Number) String() string {
+	if n.IsZero() {
+		return ""
+	}
+	return n.Local()
+}
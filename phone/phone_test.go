@@ -0,0 +1,87 @@
+package phone
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		err   error
+	}{
+		{input: "0812345678", want: "0812345678"},
+		{input: "081-234-5678", want: "0812345678"},
+		{input: "081.234.5678", want: "0812345678"},
+		{input: " (081) 234 5678 ", want: "0812345678"},
+		{input: "+66 81-234-5678", want: "0812345678"},
+		{input: "+66812345678", want: "0812345678"},
+		{input: "+66 (0)81 234 5678", want: "0812345678"},
+		{input: "66812345678", want: "0812345678"},
+		{input: "0066812345678", want: "0812345678"},
+		{input: "0612345678", want: "0612345678"},
+		{input: "0912345678", want: "0912345678"},
+		{input: "08123456789", err: ErrLength},
+		{input: "081234567", err: ErrLength},
+		{input: "", err: ErrLength},
+		{input: "0212345678", err: ErrPrefix},
+		{input: "+1 812 345 6789", err: ErrPrefix},
+		{input: "081-234-567x", err: ErrFormat},
+		{input: "08+12345678", err: ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumber_Forms(t *testing.T) {
+	n := MustParse("+66 81 234 5678")
+	if got := n.Local(); got != "0812345678" {
+		t.Errorf("Local() = %v, want 0812345678", got)
+	}
+	if got := n.E164(); got != "+66812345678" {
+		t.Errorf("E164() = %v, want +66812345678", got)
+	}
+	if got := n.Proxy(); got != "0066812345678" {
+		t.Errorf("Proxy() = %v, want 0066812345678", got)
+	}
+	if got := n.Format(); got != "081-234-5678" {
+		t.Errorf("Format() = %v, want 081-234-5678", got)
+	}
+}
+
+func TestNumber_Zero(t *testing.T) {
+	var n Number
+	if got := n.Format(); got != "" {
+		t.Errorf("Format() = %v, want empty", got)
+	}
+	if got := n.String(); got != "" {
+		t.Errorf("String() = %v, want empty", got)
+	}
+}
+
+func TestFromProxy(t *testing.T) {
+	n, err := FromProxy("0066812223333")
+	if err != nil {
+		t.Fatalf("FromProxy() error = %v", err)
+	}
+	if n.Local() != "0812223333" {
+		t.Errorf("FromProxy() = %v, want 0812223333", n)
+	}
+
+	for _, proxy := range []string{"0812223333", "0001812223333", "0066212223333", "006681222333X"} {
+		if _, err := FromProxy(proxy); err == nil {
+			t.Errorf("FromProxy(%q) should return error", proxy)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package thaiqrgo
+
+import (
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactionPolicy controls which values are masked before a payment is logged or shared.
+type RedactionPolicy struct {
+	// Proxies masks PromptPay proxies and other merchant account values (e.g. Tag 29 sub-tags)
+	Proxies bool
+
+	// BillerIDs masks biller IDs (Tag 30.01 and BOT Barcode biller IDs)
+	BillerIDs bool
+
+	// References masks bill payment references (Tag 30.02, 30.03, Tag 62 references
+	// and BOT Barcode references)
+	References bool
+
+	// Messages masks personal messages (Tag 81) entirely
+	Messages bool
+
+	// KeepLast is the number of trailing characters left visible in masked values
+	KeepLast int
+
+	// Mask is the ASCII character used for masking (default: 'X')
+	Mask byte
+}
+
+// DefaultRedactionPolicy masks every sensitive value, keeping the last 4 characters visible.
+var DefaultRedactionPolicy = RedactionPolicy{
+	Proxies:    true,
+	BillerIDs:  true,
+	References: true,
+	Messages:   true,
+	KeepLast:   4,
+	Mask:       'X',
+}
+
+// MaskValue masks all but the last KeepLast characters of value.
+//
+// Values not longer than KeepLast are masked entirely. The result has the same
+// length in bytes as value, so TLV structures stay intact. KeepLast counts bytes;
+// a multi-byte character cut by it is masked whole.
+func (p RedactionPolicy) MaskValue(value string) string {
+	return p.mask(value, p.KeepLast)
+}
+
+func (p RedactionPolicy) mask(value string, keepLast int) string {
+	mask := p.Mask
+	if mask == 0 {
+		mask = 'X'
+	}
+
+	start := len(value)
+	if keepLast > 0 && len(value) > keepLast {
+		start -= keepLast
+	}
+	for start < len(value) && !utf8.RuneStart(value[start]) {
+		start++
+	}
+	return strings.Repeat(string(mask), start) + value[start:]
+}
+
+// Sensitive reports whether the policy masks the given tag or sub-tag.
+//
+// For a template tag with an empty subTagID, it reports whether any of its
+// sub-tags is masked, so that a template that cannot be decoded is masked whole.
+func (p RedactionPolicy) Sensitive(tagID, subTagID string) bool {
+	switch {
+	case tagID == "81":
+		return p.Messages
+	case tagID == "30":
+		switch subTagID {
+		case "":
+			return p.BillerIDs || p.References
+		case "01":
+			return p.BillerIDs
+		case "02", "03":
+			return p.References
+		}
+	case tagID == "62":
+		switch subTagID {
+		case "", "01", "02", "05", "06", "07":
+			return p.References
+		}
+	case tagID >= "26" && tagID <= "51":
+		return p.Proxies && subTagID != "00"
+	}
+	return false
+}
+
+// redactValue masks a tag value if the policy marks it as sensitive.
+func (p RedactionPolicy) redactValue(tagID, subTagID, value string) string {
+	if !p.Sensitive(tagID, subTagID) {
+		return value
+	}
+	if tagID == "81" {
+		return p.mask(value, 0)
+	}
+	return p.MaskValue(value)
+}
+
+// RedactedPayload returns the payload with sensitive values masked according to the policy.
+//
+// Tag IDs and lengths are kept, so the result still decodes as TLV, but the
+// CRC no longer matches. Use it for support tickets and logs, never for payment.
+// Templates are decoded if the QR code was parsed without sub-tags; templates
+// that are not valid TLV are masked whole.
+func (q *EMVCoQR) RedactedPayload(policy RedactionPolicy) string {
+	tags := make([]TLVTag, len(q.tags))
+	for i, tag := range q.tags {
+		tags[i] = tag
+		sub := tag.SubTags
+		if len(sub) == 0 && isTemplateTag(tag.ID) {
+			sub, _ = Decode(tag.Value)
+		}
+		if len(sub) == 0 {
+			tags[i].Value = policy.redactValue(tag.ID, "", tag.Value)
+			continue
+		}
+
+		tags[i].SubTags = make([]TLVTag, len(sub))
+		for j, sub := range sub {
+			tags[i].SubTags[j] = TLVTag{
+				ID:     sub.ID,
+				Value:  policy.redactValue(tag.ID, sub.ID, sub.Value),
+				Length: sub.Length,
+			}
+		}
+	}
+	return Encode(tags)
+}
+
+// Redact returns a slog.LogValuer that logs the QR code using the given policy.
+func (q *EMVCoQR) Redact(policy RedactionPolicy) slog.LogValuer {
+	return redactedQR{qr: q, policy: policy}
+}
+
+// LogValue implements slog.LogValuer using DefaultRedactionPolicy.
+func (q *EMVCoQR) LogValue() slog.Value {
+	return redactedQR{qr: q, policy: DefaultRedactionPolicy}.LogValue()
+}
+
+// logFields maps tag paths to log attribute keys.
+var logFields = []struct {
+	tagID, subTagID, key string
+}{
+	{"01", "", "initiation"},
+	{"29", "01", "msisdn"},
+	{"29", "02", "natid"},
+	{"29", "03", "ewallet_id"},
+	{"29", "04", "bank_account"},
+	{"30", "01", "biller_id"},
+	{"30", "02", "ref1"},
+	{"30", "03", "ref2"},
+	{"62", "07", "ref3"},
+	{"54", "", "amount"},
+	{"53", "", "currency"},
+	{"58", "", "country"},
+	{"59", "", "merchant_name"},
+	{"60", "", "merchant_city"},
+	{"81", "", "message"},
+}
+
+type redactedQR struct {
+	qr     *EMVCoQR
+	policy RedactionPolicy
+}
+
+func (r redactedQR) LogValue() slog.Value {
+	if r.qr == nil {
+		return slog.Value{}
+	}
+
+	attrs := []slog.Attr{slog.String("payload", r.qr.RedactedPayload(r.policy))}
+	for _, field := range logFields {
+		value, ok := r.qr.fieldValue(field.tagID, field.subTagID)
+		if !ok {
+			continue
+		}
+		value = r.policy.redactValue(field.tagID, field.subTagID, value)
+		attrs = append(attrs, slog.String(field.key, value))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// fieldValue returns the value of a tag or sub-tag, decoding the template if
+// the QR code was parsed without sub-tags.
+func (q *EMVCoQR) fieldValue(tagID, subTagID string) (string, bool) {
+	if subTagID == "" {
+		tag := q.GetTag(tagID, "")
+		if tag == nil {
+			return "", false
+		}
+		return tag.Value, true
+	}
+	for _, sub := range q.templateTags(tagID) {
+		if sub.ID == subTagID {
+			return sub.Value, true
+		}
+	}
+	return "", false
+}
+
+// RedactedString returns the barcode string with sensitive values masked according to the policy.
+//
+// The field layout is kept. Use it for support tickets and logs, never for payment.
+func (b *BOTBarcode) RedactedString(policy RedactionPolicy) string {
+	redacted := *b
+	if policy.BillerIDs {
+		redacted.BillerID = policy.MaskValue(b.BillerID)
+	}
+	if policy.References {
+		redacted.Ref1 = policy.MaskValue(b.Ref1)
+		if b.Ref2 != nil {
+			ref2 := policy.MaskValue(*b.Ref2)
+			redacted.Ref2 = &ref2
+		}
+	}
+	return redacted.String()
+}
+
+// Redact returns a slog.LogValuer that logs the barcode using the given policy.
+func (b *BOTBarcode) Redact(policy RedactionPolicy) slog.LogValuer {
+	return redactedBarcode{barcode: b, policy: policy}
+}
+
+// LogValue implements slog.LogValuer using DefaultRedactionPolicy.
+func (b *BOTBarcode) LogValue() slog.Value {
+	return redactedBarcode{barcode: b, policy: DefaultRedactionPolicy}.LogValue()
+}
+
+type redactedBarcode struct {
+	barcode *BOTBarcode
+	policy  RedactionPolicy
+}
+
+func (r redactedBarcode) LogValue() slog.Value {
+	if r.barcode == nil {
+		return slog.Value{}
+	}
+
+	b := r.barcode
+	billerID, ref1 := b.BillerID, b.Ref1
+	if r.policy.BillerIDs {
+		billerID = r.policy.MaskValue(billerID)
+	}
+	if r.policy.References {
+		ref1 = r.policy.MaskValue(ref1)
+	}
+
+	attrs := []slog.Attr{
+		slog.String("biller_id", billerID),
+		slog.String("ref1", ref1),
+	}
+	if b.Ref2 != nil {
+		ref2 := *b.Ref2
+		if r.policy.References {
+			ref2 = r.policy.MaskValue(ref2)
+		}
+		attrs = append(attrs, slog.String("ref2", ref2))
+	}
+	if b.Amount != nil {
+		attrs = append(attrs, slog.Any("amount", *b.Amount))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,147 @@
+package thaiqrgo
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRedactionPolicy_MaskValue(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "0066801111111", want: "XXXXXXXXX1111"},
+		{value: "1234", want: "XXXX"},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultRedactionPolicy.MaskValue(tt.value); got != tt.want {
+			t.Errorf("MaskValue(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	policy := RedactionPolicy{KeepLast: 2, Mask: '*'}
+	if got := policy.MaskValue("123456"); got != "****56" {
+		t.Errorf("MaskValue() with custom policy = %v, want ****56", got)
+	}
+
+	// Thai characters take 3 bytes each: only whole characters are kept visible
+	for keepLast, want := range map[int]string{2: strings.Repeat("*", 18), 4: strings.Repeat("*", 15) + "ง"} {
+		policy.KeepLast = keepLast
+		got := policy.MaskValue("ใบแจ้ง")
+		if got != want || !utf8.ValidString(got) {
+			t.Errorf("MaskValue(%q) with KeepLast %d = %q, want %q", "ใบแจ้ง", keepLast, got, want)
+		}
+	}
+}
+
+func TestEMVCoQR_RedactedPayload(t *testing.T) {
+	payload := "00020101021130620016A000000677010112011301122334455660211CUSTOMER0010306INV00153037645802TH62070703SCB6304780E"
+	qr, err := Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := qr.RedactedPayload(DefaultRedactionPolicy)
+	want := "00020101021130620016A0000006770101120113XXXXXXXXX55660211XXXXXXXR0010306XXV00153037645802TH62070703XXX6304780E"
+	if got != want {
+		t.Errorf("RedactedPayload() = %v, want %v", got, want)
+	}
+
+	// The redacted payload keeps its TLV structure
+	redacted, err := Parse(got, false, true)
+	if err != nil {
+		t.Fatalf("Parse() redacted payload error = %v", err)
+	}
+	if redacted.GetTagValue("30", "00") != "A000000677010112" {
+		t.Errorf("Parse() redacted Tag 30.00 = %v, want A000000677010112", redacted.GetTagValue("30", "00"))
+	}
+}
+
+func TestEMVCoQR_RedactedPayload_WithoutSubTags(t *testing.T) {
+	payload := "00020101021129370016A000000677010111021311012345678915802TH53037646304D0AC"
+	qr, err := Parse(payload, false, false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	got := qr.RedactedPayload(DefaultRedactionPolicy)
+	want := "00020101021129370016A0000006770101110213XXXXXXXXX78915802TH53037646304D0AC"
+	if got != want {
+		t.Errorf("RedactedPayload() = %v, want %v", got, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("parsed", "qr", qr)
+	if strings.Contains(buf.String(), "1101234567891") {
+		t.Errorf("log output contains the national ID: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"natid":"XXXXXXXXX7891"`) {
+		t.Errorf("log output missing natid: %s", buf.String())
+	}
+
+	// A template that is not valid TLV is masked whole
+	qr, err = Parse("00020101021129101101234567", false, false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if got, want := qr.RedactedPayload(DefaultRedactionPolicy), "0002010102112910XXXXXX4567"; got != want {
+		t.Errorf("RedactedPayload() = %v, want %v", got, want)
+	}
+}
+
+func TestEMVCoQR_LogValue(t *testing.T) {
+	payload := "00020101021229390016A000000677010111031514000080111111153037645802TH540510.05814800480065006C006C006F00200057006F0072006C006400216304F5A2"
+	qr, err := Parse(payload, true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Info("parsed", "qr", qr)
+
+	out := buf.String()
+	for _, secret := range []string{"140000801111111", "00480065006C"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output contains %q: %s", secret, out)
+		}
+	}
+	for _, want := range []string{`"ewallet_id":"XXXXXXXXXXX1111"`, `"amount":"10.05"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %s: %s", want, out)
+		}
+	}
+
+	buf.Reset()
+	logger.Info("parsed", "qr", qr.Redact(RedactionPolicy{}))
+	if !strings.Contains(buf.String(), "140000801111111") {
+		t.Errorf("log output with empty policy should not mask values: %s", buf.String())
+	}
+}
+
+func TestBOTBarcode_Redaction(t *testing.T) {
+	barcode, err := ParseBarcode("|099400016550100\r123456789012\r670429\r364922")
+	if err != nil {
+		t.Fatalf("ParseBarcode() error = %v", err)
+	}
+
+	got := barcode.RedactedString(DefaultRedactionPolicy)
+	want := "|XXXXXXXXXXX0100\rXXXXXXXX9012\rXX0429\r364922"
+	if got != want {
+		t.Errorf("RedactedString() = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("barcode", "barcode", barcode)
+	if strings.Contains(buf.String(), "123456789012") {
+		t.Errorf("log output contains Ref1: %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"amount":3649.22`) {
+		t.Errorf("log output missing amount: %s", buf.String())
+	}
+}
@@ -0,0 +1,136 @@
+package reference
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klimakov/thai-qr-go/internal"
+)
+
+// Scheme calculates check digits.
+type Scheme interface {
+	// CheckDigit returns the check digit of a non-empty string of digits
+	CheckDigit(digits string) (byte, error)
+}
+
+// registeredScheme is a scheme looked up by name in the check digit registry.
+type registeredScheme string
+
+func (s registeredScheme) CheckDigit(digits string) (byte, error) {
+	return internal.CheckDigit(string(s), digits)
+}
+
+// Built-in schemes.
+var (
+	// Mod10 is the Luhn algorithm
+	Mod10 Scheme = registeredScheme(internal.CheckDigitMod10)
+
+	// Mod11 is a modulus 11 check with weights 2-7 from the right.
+	// Remainders that would give 10 or 11 map to 0.
+	Mod11 Scheme = registeredScheme(internal.CheckDigitMod11)
+)
+
+// Weighted is a weighted-sum check digit scheme, as used by many banks.
+//
+// Each digit is multiplied by a weight, starting from the rightmost digit and
+// cycling through Weights. The check digit is the sum modulo Modulus, or its
+// complement, reduced to a single digit.
+type Weighted struct {
+	// Weights are applied from the rightmost digit, repeating if needed (not negative)
+	Weights []int
+
+	// Modulus is the modulus of the sum (default: 10)
+	Modulus int
+
+	// Complement uses (Modulus - sum%Modulus) % Modulus instead of sum%Modulus
+	Complement bool
+}
+
+// CheckDigit implements the Scheme interface.
+func (w Weighted) CheckDigit(digits string) (byte, error) {
+	if len(w.Weights) == 0 {
+		return 0, errors.New("invalid weighted scheme: no weights")
+	}
+	for _, weight := range w.Weights {
+		if weight < 0 {
+			return 0, fmt.Errorf("invalid weighted scheme: negative weight %d", weight)
+		}
+	}
+	modulus := w.Modulus
+	if modulus == 0 {
+		modulus = 10
+	}
+	if modulus < 2 {
+		return 0, fmt.Errorf("invalid weighted scheme: modulus %d", modulus)
+	}
+	if digits == "" || !isDigits(digits) {
+		return 0, fmt.Errorf("invalid check digit input %q: must contain digits only", digits)
+	}
+
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		sum += int(digits[len(digits)-1-i]-'0') * w.Weights[i%len(w.Weights)]
+	}
+	r := sum % modulus
+	if w.Complement {
+		r = (modulus - r) % modulus
+	}
+	return byte('0' + r%10), nil
+}
+
+// Register makes a scheme available by name, e.g. in biller.Rule.CheckDigit,
+// or replaces the scheme registered under the name.
+//
+// Returns an error for an empty name, a nil scheme, or a built-in name
+// ("mod10" or "mod11"), which cannot be replaced.
+func Register(name string, scheme Scheme) error {
+	if scheme == nil {
+		return fmt.Errorf("invalid check digit scheme %q: nil", name)
+	}
+
+	compute := scheme.CheckDigit
+	if s, ok := scheme.(registeredScheme); ok {
+		// Store the function itself: the scheme looks itself up by name, so
+		// registering it under its own name would make it call itself
+		if compute, ok = internal.CheckDigitFunc(string(s)); !ok {
+			return fmt.Errorf("unknown check digit scheme: %q", string(s))
+		}
+	}
+	return internal.RegisterCheckDigit(name, compute)
+}
+
+// Lookup returns the scheme registered under the given name.
+func Lookup(name string) (Scheme, bool) {
+	if !internal.HasCheckDigit(name) {
+		return nil, false
+	}
+	return registeredScheme(name), true
+}
+
+// Append returns digits followed by their check digit.
+func Append(scheme Scheme, digits string) (string, error) {
+	check, err := scheme.CheckDigit(digits)
+	if err != nil {
+		return "", err
+	}
+	return digits + string(check), nil
+}
+
+// Verify reports whether the last character of value is the check digit of
+// the digits before it.
+func Verify(scheme Scheme, value string) bool {
+	if len(value) < 2 {
+		return false
+	}
+	check, err := scheme.CheckDigit(value[:len(value)-1])
+	return err == nil && value[len(value)-1] == check
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
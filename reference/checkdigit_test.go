@@ -0,0 +1,109 @@
+package reference
+
+import "testing"
+
+func TestSchemes(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme Scheme
+		digits string
+		want   byte
+	}{
+		{"luhn", Mod10, "7992739871", '3'},
+		{"mod11", Mod11, "261533", '9'},
+		{"weighted", Weighted{Weights: []int{1, 3}}, "1234", '8'},
+		{"weighted complement mod 11", Weighted{Weights: []int{2, 3, 4, 5, 6, 7}, Modulus: 11, Complement: true}, "261533", '9'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scheme.CheckDigit(tt.digits)
+			if err != nil {
+				t.Fatalf("CheckDigit() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckDigit(%q) = %c, want %c", tt.digits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeighted_Invalid(t *testing.T) {
+	if _, err := (Weighted{}).CheckDigit("123"); err == nil {
+		t.Error("CheckDigit() without weights should fail")
+	}
+	if _, err := (Weighted{Weights: []int{1}, Modulus: 1}).CheckDigit("123"); err == nil {
+		t.Error("CheckDigit() with modulus 1 should fail")
+	}
+	if _, err := (Weighted{Weights: []int{1, -3}}).CheckDigit("123"); err == nil {
+		t.Error("CheckDigit() with a negative weight should fail")
+	}
+	if _, err := (Weighted{Weights: []int{1}}).CheckDigit("12a"); err == nil {
+		t.Error("CheckDigit() with letters should fail")
+	}
+}
+
+func TestAppendVerify(t *testing.T) {
+	got, err := Append(Mod10, "7992739871")
+	if err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	if got != "79927398713" {
+		t.Errorf("Append() = %v, want %v", got, "79927398713")
+	}
+	if !Verify(Mod10, got) {
+		t.Errorf("Verify(%q) = false, want true", got)
+	}
+	if Verify(Mod10, "79927398710") {
+		t.Error("Verify() with wrong check digit = true, want false")
+	}
+	if Verify(Mod10, "7") {
+		t.Error("Verify() with single digit = true, want false")
+	}
+}
+
+func TestRegister(t *testing.T) {
+	if err := Register("test-weighted", Weighted{Weights: []int{1, 3}}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	scheme, ok := Lookup("test-weighted")
+	if !ok {
+		t.Fatal("Lookup() after Register() = false")
+	}
+	if got, _ := scheme.CheckDigit("1234"); got != '8' {
+		t.Errorf("CheckDigit() = %c, want 2", got)
+	}
+	if _, ok := Lookup("unknown"); ok {
+		t.Error("Lookup(unknown) = true, want false")
+	}
+}
+
+func TestRegister_RegisteredScheme(t *testing.T) {
+	for _, name := range []string{"mod10", "mod11", ""} {
+		if err := Register(name, Weighted{Weights: []int{1}}); err == nil {
+			t.Errorf("Register(%q) should return error", name)
+		}
+	}
+	if err := Register("test-nil", nil); err == nil {
+		t.Error("Register() with nil scheme should return error")
+	}
+
+	// Registered schemes can be registered again, also under their own name
+	if err := Register("mod10", Mod10); err == nil {
+		t.Error("Register(mod10, Mod10) should return error")
+	}
+	if err := Register("test-luhn", Mod10); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	scheme, _ := Lookup("test-luhn")
+	if err := Register("test-luhn", scheme); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if got, err := scheme.CheckDigit("7992739871"); err != nil || got != '3' {
+		t.Errorf("CheckDigit() = %c, %v, want 3", got, err)
+	}
+	if got, _ := Mod10.CheckDigit("7992739871"); got != '3' {
+		t.Errorf("Mod10.CheckDigit() = %c, want 3", got)
+	}
+}
@@ -0,0 +1,8 @@
+// Package reference builds and verifies bill payment references (Ref1 and Ref2).
+//
+// A Generator fills a template such as "{customer:6}{date:YYMMDD}{check}" from
+// invoice data and appends a check digit computed with a Scheme: Luhn (mod 10),
+// mod 11, or a bank-specific weighted sum. An Issuer ties generators to a biller
+// and produces ready-to-use generate.BillPaymentConfig and generate.BOTBarcodeConfig
+// values. Both verify incoming references the same way they were built.
+package reference
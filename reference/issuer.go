@@ -0,0 +1,99 @@
+package reference
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/biller"
+	"github.com/klimakov/thai-qr-go/generate"
+)
+
+// Issuer issues references for a single biller.
+type Issuer struct {
+	// BillerID is the biller identifier (Tax ID + Suffix)
+	BillerID string
+
+	// Ref1 builds reference 1
+	Ref1 *Generator
+
+	// Ref2 builds reference 2 (optional)
+	Ref2 *Generator
+
+	// Directory is the biller directory passed to the generated configs
+	// (default: biller.Default)
+	Directory *biller.Directory
+}
+
+// refs builds Ref1 and, if configured, Ref2.
+func (i *Issuer) refs(v Values) (string, *string, error) {
+	if i.Ref1 == nil {
+		return "", nil, errors.New("reference issuer has no Ref1 generator")
+	}
+	ref1, err := i.Ref1.Generate(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("Ref1: %w", err)
+	}
+	if i.Ref2 == nil {
+		return ref1, nil, nil
+	}
+	ref2, err := i.Ref2.Generate(v)
+	if err != nil {
+		return "", nil, fmt.Errorf("Ref2: %w", err)
+	}
+	return ref1, &ref2, nil
+}
+
+// BillPaymentConfig builds the references for an invoice and returns a
+// configuration for generate.BillPayment.
+func (i *Issuer) BillPaymentConfig(v Values, amount *thaiqrgo.Amount) (generate.BillPaymentConfig, error) {
+	ref1, ref2, err := i.refs(v)
+	if err != nil {
+		return generate.BillPaymentConfig{}, err
+	}
+	return generate.BillPaymentConfig{
+		BillerID:  i.BillerID,
+		Amount:    amount,
+		Ref1:      ref1,
+		Ref2:      ref2,
+		Directory: i.Directory,
+	}, nil
+}
+
+// BOTBarcodeConfig builds the references for an invoice and returns a
+// configuration for generate.BOTBarcode.
+func (i *Issuer) BOTBarcodeConfig(v Values, amount *thaiqrgo.Amount) (generate.BOTBarcodeConfig, error) {
+	ref1, ref2, err := i.refs(v)
+	if err != nil {
+		return generate.BOTBarcodeConfig{}, err
+	}
+	return generate.BOTBarcodeConfig{
+		BillerID:  i.BillerID,
+		Ref1:      ref1,
+		Ref2:      ref2,
+		Amount:    amount,
+		Directory: i.Directory,
+	}, nil
+}
+
+// Verify checks incoming references, e.g. from a parsed QR code or BOT Barcode.
+//
+// Ref2 is only checked if the issuer has a Ref2 generator.
+func (i *Issuer) Verify(ref1 string, ref2 *string) error {
+	if i.Ref1 == nil {
+		return errors.New("reference issuer has no Ref1 generator")
+	}
+	if err := i.Ref1.Verify(ref1); err != nil {
+		return fmt.Errorf("Ref1: %w", err)
+	}
+	if i.Ref2 == nil {
+		return nil
+	}
+	if ref2 == nil {
+		return fmt.Errorf("Ref2: %w: missing", ErrFormat)
+	}
+	if err := i.Ref2.Verify(*ref2); err != nil {
+		return fmt.Errorf("Ref2: %w", err)
+	}
+	return nil
+}
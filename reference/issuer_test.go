@@ -0,0 +1,87 @@
+package reference
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/biller"
+	"github.com/klimakov/thai-qr-go/generate"
+)
+
+func testIssuer(t *testing.T) *Issuer {
+	t.Helper()
+	directory, err := biller.NewDirectory(biller.Biller{
+		TaxID:  "0994000165501",
+		Suffix: "00",
+		Ref1:   biller.Rule{Required: true, MaxLength: 12, Charset: biller.CharsetNumeric, CheckDigit: biller.CheckDigitMod10},
+	})
+	if err != nil {
+		t.Fatalf("NewDirectory() error = %v", err)
+	}
+	return &Issuer{
+		BillerID:  "099400016550100",
+		Ref1:      MustNewGenerator("{customer:6}{date:YYMM}{check}", Mod10),
+		Ref2:      MustNewGenerator("INV{invoice}", nil),
+		Directory: directory,
+	}
+}
+
+func TestIssuer_BOTBarcodeConfig(t *testing.T) {
+	issuer := testIssuer(t)
+	amount := thaiqrgo.MustParseAmount("364.92")
+	values := Values{
+		Fields: map[string]string{"customer": "1234", "invoice": "77"},
+		Date:   time.Date(2024, time.April, 29, 0, 0, 0, 0, time.UTC),
+	}
+
+	config, err := issuer.BOTBarcodeConfig(values, &amount)
+	if err != nil {
+		t.Fatalf("BOTBarcodeConfig() error = %v", err)
+	}
+	got, err := generate.BOTBarcode(config)
+	if err != nil {
+		t.Fatalf("BOTBarcode() error = %v", err)
+	}
+	want := "|099400016550100\r00123424046\rINV77\r36492"
+	if got != want {
+		t.Errorf("BOTBarcode() = %q, want %q", got, want)
+	}
+
+	if err := issuer.Verify(config.Ref1, config.Ref2); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestIssuer_BillPaymentConfig(t *testing.T) {
+	issuer := testIssuer(t)
+	values := Values{
+		Fields: map[string]string{"customer": "1234", "invoice": "77"},
+		Date:   time.Date(2024, time.April, 29, 0, 0, 0, 0, time.UTC),
+	}
+
+	config, err := issuer.BillPaymentConfig(values, nil)
+	if err != nil {
+		t.Fatalf("BillPaymentConfig() error = %v", err)
+	}
+	if _, err := generate.BillPayment(config); err != nil {
+		t.Errorf("BillPayment() error = %v", err)
+	}
+
+	if _, err := issuer.BillPaymentConfig(Values{}, nil); err == nil {
+		t.Error("BillPaymentConfig() with missing fields should fail")
+	}
+}
+
+func TestIssuer_Verify(t *testing.T) {
+	issuer := testIssuer(t)
+	ref2 := "INV77"
+
+	if err := issuer.Verify("00123424045", &ref2); !errors.Is(err, ErrCheckDigit) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrCheckDigit)
+	}
+	if err := issuer.Verify("00123424046", nil); !errors.Is(err, ErrFormat) {
+		t.Errorf("Verify() error = %v, want %v", err, ErrFormat)
+	}
+}
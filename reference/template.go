@@ -0,0 +1,313 @@
+package reference
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxLength is the maximum length of a generated reference.
+const MaxLength = 20
+
+// Errors returned when verifying a reference.
+var (
+	// ErrFormat indicates that a reference does not match the template
+	ErrFormat = errors.New("reference does not match template")
+
+	// ErrCheckDigit indicates that the check digit of a reference is wrong
+	ErrCheckDigit = errors.New("reference check digit mismatch")
+)
+
+// Values holds the data a reference is built from.
+type Values struct {
+	// Fields maps placeholder names to values, e.g. "invoice" or "customer"
+	Fields map[string]string
+
+	// Date is used by {date:...} placeholders
+	Date time.Time
+}
+
+type partKind int
+
+const (
+	partLiteral partKind = iota
+	partField
+	partDate
+	partCheck
+)
+
+type part struct {
+	kind  partKind
+	text  string // literal text, field name or date layout
+	width int    // fixed field width, 0 if variable
+}
+
+// Generator builds and verifies references from a template.
+//
+// A template is literal text with placeholders:
+//
+//	{name}          value of Values.Fields["name"]
+//	{name:N}        the same, left-padded with zeros to N characters
+//	{date:LAYOUT}   Values.Date with YYYY, YY, MM, DD, or Buddhist era BBBB, BB
+//	{check}         check digit over all digits before it
+//
+// Field values must be alphanumeric. For example, "INV{invoice:6}{check}"
+// with invoice "42" gives "INV0000422" under Mod10.
+type Generator struct {
+	template string
+	scheme   Scheme
+	parts    []part
+	pattern  *regexp.Regexp
+	check    int // index of the check digit submatch, 0 if none
+}
+
+// NewGenerator compiles a template.
+//
+// The scheme is only used by {check} and may be nil if the template has none.
+// Returns an error if the template is malformed.
+func NewGenerator(template string, scheme Scheme) (*Generator, error) {
+	g := &Generator{template: template, scheme: scheme}
+	if err := g.compile(); err != nil {
+		return nil, fmt.Errorf("invalid reference template %q: %w", template, err)
+	}
+	return g, nil
+}
+
+// MustNewGenerator is like NewGenerator but panics if the template is malformed.
+func MustNewGenerator(template string, scheme Scheme) *Generator {
+	g, err := NewGenerator(template, scheme)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// Template returns the template the generator was compiled from.
+func (g *Generator) Template() string {
+	return g.template
+}
+
+func (g *Generator) compile() error {
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	groups := 0
+
+	rest := g.template
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			open = len(rest)
+		}
+		if open > 0 {
+			literal := rest[:open]
+			if strings.ContainsRune(literal, '}') {
+				return errors.New("unmatched '}'")
+			}
+			g.parts = append(g.parts, part{kind: partLiteral, text: literal})
+			pattern.WriteString(regexp.QuoteMeta(literal))
+			rest = rest[open:]
+			continue
+		}
+
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return errors.New("unterminated placeholder")
+		}
+		name, arg, hasArg := strings.Cut(rest[1:end], ":")
+		rest = rest[end+1:]
+
+		switch {
+		case name == "check":
+			if hasArg {
+				return errors.New("{check} takes no argument")
+			}
+			if g.scheme == nil {
+				return errors.New("{check} needs a check digit scheme")
+			}
+			if g.check != 0 {
+				return errors.New("more than one {check}")
+			}
+			groups++
+			g.check = groups
+			g.parts = append(g.parts, part{kind: partCheck})
+			pattern.WriteString(`([0-9])`)
+		case name == "date":
+			expr, err := dateExpr(arg)
+			if err != nil {
+				return err
+			}
+			g.parts = append(g.parts, part{kind: partDate, text: arg})
+			groups++
+			pattern.WriteString("(" + expr + ")")
+		case name == "":
+			return errors.New("empty placeholder name")
+		default:
+			width := 0
+			if hasArg {
+				n, err := strconv.Atoi(arg)
+				if err != nil || n <= 0 || n > MaxLength {
+					return fmt.Errorf("invalid width %q for {%s}", arg, name)
+				}
+				width = n
+			}
+			g.parts = append(g.parts, part{kind: partField, text: name, width: width})
+			groups++
+			if width > 0 {
+				fmt.Fprintf(&pattern, "([0-9A-Za-z]{%d})", width)
+			} else {
+				pattern.WriteString("([0-9A-Za-z]+)")
+			}
+		}
+	}
+
+	pattern.WriteString("$")
+	g.pattern = regexp.MustCompile(pattern.String())
+	return nil
+}
+
+// dateTokens are the date layout tokens, longest first.
+var dateTokens = []string{"BBBB", "YYYY", "BB", "YY", "MM", "DD"}
+
+// dateExpr validates a date layout and returns a regular expression matching it.
+func dateExpr(layout string) (string, error) {
+	if layout == "" {
+		return "", errors.New("{date} needs a layout, e.g. {date:YYMMDD}")
+	}
+	var expr strings.Builder
+	for layout != "" {
+		token := dateToken(layout)
+		if token == "" {
+			return "", fmt.Errorf("invalid date layout at %q", layout)
+		}
+		fmt.Fprintf(&expr, "[0-9]{%d}", len(token))
+		layout = layout[len(token):]
+	}
+	return expr.String(), nil
+}
+
+func dateToken(layout string) string {
+	for _, token := range dateTokens {
+		if strings.HasPrefix(layout, token) {
+			return token
+		}
+	}
+	return ""
+}
+
+func formatDate(layout string, date time.Time) string {
+	var b strings.Builder
+	for layout != "" {
+		token := dateToken(layout)
+		switch token {
+		case "BBBB":
+			fmt.Fprintf(&b, "%04d", date.Year()+543)
+		case "YYYY":
+			fmt.Fprintf(&b, "%04d", date.Year())
+		case "BB":
+			fmt.Fprintf(&b, "%02d", (date.Year()+543)%100)
+		case "YY":
+			fmt.Fprintf(&b, "%02d", date.Year()%100)
+		case "MM":
+			fmt.Fprintf(&b, "%02d", int(date.Month()))
+		case "DD":
+			fmt.Fprintf(&b, "%02d", date.Day())
+		}
+		layout = layout[len(token):]
+	}
+	return b.String()
+}
+
+// Generate builds a reference from values.
+//
+// Returns an error if a field is missing, not alphanumeric or longer than its
+// width, if the template uses a date and Date is zero, or if the reference is
+// longer than MaxLength.
+func (g *Generator) Generate(v Values) (string, error) {
+	var b strings.Builder
+	for _, p := range g.parts {
+		switch p.kind {
+		case partLiteral:
+			b.WriteString(p.text)
+		case partField:
+			value, ok := v.Fields[p.text]
+			if !ok || value == "" {
+				return "", fmt.Errorf("reference field %q is missing", p.text)
+			}
+			if !isAlphanumeric(value) {
+				return "", fmt.Errorf("reference field %q must be alphanumeric: %q", p.text, value)
+			}
+			if p.width > 0 {
+				if len(value) > p.width {
+					return "", fmt.Errorf("reference field %q is longer than %d characters: %q", p.text, p.width, value)
+				}
+				value = strings.Repeat("0", p.width-len(value)) + value
+			}
+			b.WriteString(value)
+		case partDate:
+			if v.Date.IsZero() {
+				return "", errors.New("reference date is missing")
+			}
+			b.WriteString(formatDate(p.text, v.Date))
+		case partCheck:
+			check, err := g.scheme.CheckDigit(digitsOf(b.String()))
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(check)
+		}
+	}
+
+	ref := b.String()
+	if len(ref) > MaxLength {
+		return "", fmt.Errorf("reference %q is longer than %d characters", ref, MaxLength)
+	}
+	return ref, nil
+}
+
+// Verify checks that a reference matches the template and, if the template
+// has a {check} placeholder, that its check digit is correct.
+//
+// Returns an error wrapping ErrFormat or ErrCheckDigit.
+func (g *Generator) Verify(ref string) error {
+	match := g.pattern.FindStringSubmatchIndex(ref)
+	if match == nil {
+		return fmt.Errorf("%w: %q", ErrFormat, ref)
+	}
+	if g.check == 0 {
+		return nil
+	}
+
+	start := match[2*g.check]
+	check, err := g.scheme.CheckDigit(digitsOf(ref[:start]))
+	if err != nil {
+		return err
+	}
+	if ref[start] != check {
+		return fmt.Errorf("%w: %q", ErrCheckDigit, ref)
+	}
+	return nil
+}
+
+// digitsOf returns the digits of s, dropping letters and separators.
+func digitsOf(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+func isAlphanumeric(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
+			return false
+		}
+	}
+	return true
+}
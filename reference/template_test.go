@@ -0,0 +1,116 @@
+package reference
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGenerator_Generate(t *testing.T) {
+	date := time.Date(2025, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template string
+		scheme   Scheme
+		values   Values
+		want     string
+	}{
+		{"padded field", "INV{invoice:6}{check}", Mod10, Values{Fields: map[string]string{"invoice": "42"}}, "INV0000422"},
+		{"date", "{customer}{date:YYMMDD}", nil, Values{Fields: map[string]string{"customer": "C12"}, Date: date}, "C12250307"},
+		{"buddhist era", "{date:BBBBMMDD}", nil, Values{Date: date}, "25680307"},
+		{"short buddhist era", "{date:BB}-{n}", nil, Values{Fields: map[string]string{"n": "1"}, Date: date}, "68-1"},
+		{"mod11", "{customer:5}{check}", Mod11, Values{Fields: map[string]string{"customer": "61533"}}, "615331"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewGenerator(tt.template, tt.scheme)
+			if err != nil {
+				t.Fatalf("NewGenerator() error = %v", err)
+			}
+			got, err := g.Generate(tt.values)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Generate() = %v, want %v", got, tt.want)
+			}
+			if err := g.Verify(got); err != nil {
+				t.Errorf("Verify(%q) error = %v", got, err)
+			}
+		})
+	}
+}
+
+func TestGenerator_GenerateErrors(t *testing.T) {
+	g := MustNewGenerator("{invoice:4}{date:YYMM}{check}", Mod10)
+	date := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		values Values
+	}{
+		{"missing field", Values{Date: date}},
+		{"too long", Values{Fields: map[string]string{"invoice": "12345"}, Date: date}},
+		{"not alphanumeric", Values{Fields: map[string]string{"invoice": "1-2"}, Date: date}},
+		{"missing date", Values{Fields: map[string]string{"invoice": "1"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.Generate(tt.values); err == nil {
+				t.Error("Generate() error = nil, want error")
+			}
+		})
+	}
+
+	long := MustNewGenerator("{a}", nil)
+	if _, err := long.Generate(Values{Fields: map[string]string{"a": "123456789012345678901"}}); err == nil {
+		t.Error("Generate() of 21 characters should fail")
+	}
+}
+
+func TestNewGenerator_Invalid(t *testing.T) {
+	templates := []string{
+		"{invoice",
+		"INV}",
+		"{}",
+		"{invoice:0}",
+		"{invoice:x}",
+		"{date}",
+		"{date:YYQQ}",
+		"{check}", // no scheme
+	}
+	for _, template := range templates {
+		if _, err := NewGenerator(template, nil); err == nil {
+			t.Errorf("NewGenerator(%q) error = nil, want error", template)
+		}
+	}
+	if _, err := NewGenerator("{a}{check}{check}", Mod10); err == nil {
+		t.Error("NewGenerator() with two {check} should fail")
+	}
+}
+
+func TestGenerator_Verify(t *testing.T) {
+	g := MustNewGenerator("INV{invoice:6}{check}", Mod10)
+
+	tests := []struct {
+		ref  string
+		want error
+	}{
+		{"INV0000422", nil},
+		{"INV0000421", ErrCheckDigit},
+		{"INV000042", ErrFormat},
+		{"XYZ0000422", ErrFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			err := g.Verify(tt.ref)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify(%q) error = %v, want %v", tt.ref, err, tt.want)
+			}
+		})
+	}
+}
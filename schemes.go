@@ -0,0 +1,44 @@
+package thaiqrgo
+
+import "github.com/klimakov/thai-qr-go/aid"
+
+// PaymentScheme is a merchant account found in a QR code.
+type PaymentScheme struct {
+	// TagID is the merchant account tag (02-51)
+	TagID string
+
+	// AID is the application identifier: the template GUID (sub-tag 00),
+	// or the registered AID of the card scheme owning a primitive tag
+	AID string
+
+	// Name is the scheme name from the AID registry, empty if the scheme is unknown
+	Name string
+}
+
+// Schemes returns every payment scheme in the QR code, in tag order.
+//
+// A QR code can carry merchant accounts of several schemes, e.g. PromptPay
+// AnyID (Tag 29) with Bill Payment (Tag 30) and a card scheme. Schemes
+// missing from the AID registry (see package aid) are returned without a name.
+func (q *EMVCoQR) Schemes() []PaymentScheme {
+	var schemes []PaymentScheme
+	for _, tag := range q.tags {
+		if len(tag.ID) != 2 || tag.ID < "02" || tag.ID > "51" {
+			continue
+		}
+
+		found := PaymentScheme{TagID: tag.ID}
+		if tag.ID <= "25" {
+			if s, ok := aid.LookupTag(tag.ID); ok {
+				found.AID, found.Name = s.AID, s.Name
+			}
+		} else {
+			found.AID = subTagValue(q.templateTags(tag.ID), "00")
+			if s, ok := aid.Lookup(found.AID); ok {
+				found.Name = s.Name
+			}
+		}
+		schemes = append(schemes, found)
+	}
+	return schemes
+}
@@ -0,0 +1,35 @@
+package thaiqrgo
+
+import "testing"
+
+func TestEMVCoQR_Schemes(t *testing.T) {
+	payload := WithCRCTag(Encode([]TLVTag{
+		Tag("00", "01"),
+		Tag("01", "11"),
+		Tag("04", "5555555555554444"),
+		Tag("26", Encode([]TLVTag{Tag("00", "com.example.wallet"), Tag("01", "12345")})),
+		Tag("29", Encode([]TLVTag{Tag("00", aidPromptPayAnyID), Tag("01", "0066812345678")})),
+		Tag("53", "764"),
+		Tag("58", "TH"),
+	}), "63", true)
+
+	qr, err := Parse(payload, true, false)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []PaymentScheme{
+		{TagID: "04", AID: "A0000000041010", Name: "Mastercard"},
+		{TagID: "26", AID: "com.example.wallet"},
+		{TagID: "29", AID: aidPromptPayAnyID, Name: "PromptPay"},
+	}
+	got := qr.Schemes()
+	if len(got) != len(want) {
+		t.Fatalf("Schemes() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Schemes()[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package thaiqrgo
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/klimakov/thai-qr-go/internal"
+)
+
+// Signatures of QR codes issued with generate.Sign.
+const (
+	// SignatureGUID identifies the template (Tags 80-99) that holds the key ID
+	// (sub-tag 01) and signature (sub-tag 02) of a signed QR code
+	SignatureGUID = "com.github.klimakov.thaiqrgo.sig"
+
+	// DefaultSignatureLength is the default length of a signature in bytes
+	// (a truncated HMAC-SHA256, stored as twice as many hex digits)
+	DefaultSignatureLength = 8
+
+	// MinSignatureLength is the shortest accepted signature in bytes
+	MinSignatureLength = 4
+
+	// MaxSignatureLength is the longest accepted signature in bytes
+	// (an untruncated HMAC-SHA256)
+	MaxSignatureLength = 32
+)
+
+// Errors returned by SignatureVerifier.Verify.
+var (
+	// ErrUnsigned means the QR code has no signature
+	ErrUnsigned = errors.New("QR code is not signed")
+
+	// ErrUnknownSignatureKey means the QR code is signed with a key ID the verifier does not have
+	ErrUnknownSignatureKey = errors.New("QR code is signed with an unknown key")
+
+	// ErrSignatureMismatch means the signature does not match the payee, amount or reference
+	ErrSignatureMismatch = errors.New("QR code signature mismatch")
+)
+
+// SignatureKey is a secret key for signing QR codes.
+type SignatureKey struct {
+	// ID identifies the key, so that verifiers can pick it after a key rotation
+	// (alphanumeric, up to 8 characters)
+	ID string
+
+	// Secret is the HMAC-SHA256 key (at least 16 bytes)
+	Secret []byte
+}
+
+// SignatureMessage returns the data covered by a signature: the payee (merchant
+// account information, Tags 02-51, and merchant name, Tag 59), the currency and
+// amount (Tags 53 and 54), the reference and, if present, the expiry template
+// (see ExpiresAt), TLV encoded in that order.
+//
+// tags are the root tags of the payload. reference is the reference label
+// (Tag 62.05) without a signature suffix.
+func SignatureMessage(tags []TLVTag, reference string) string {
+	var signed []TLVTag
+	for _, id := range []string{"59", "53", "54"} {
+		for _, tag := range tags {
+			if tag.ID == id {
+				signed = append(signed, Tag(tag.ID, tag.Value))
+				break
+			}
+		}
+	}
+	var accounts []TLVTag
+	for _, tag := range tags {
+		if tag.ID >= "02" && tag.ID <= "51" {
+			accounts = append(accounts, Tag(tag.ID, tag.Value))
+		}
+	}
+	signed = append(signed, Tag("05", reference))
+	for _, tag := range tags {
+		if tag.ID != "62" {
+			continue
+		}
+		additional := tag.SubTags
+		if len(additional) == 0 {
+			additional, _ = Decode(tag.Value)
+		}
+		if expiry, ok := expiryTemplate(additional); ok {
+			signed = append(signed, Tag(expiry.ID, expiry.Value))
+		}
+		break
+	}
+	return Encode(accounts) + Encode(signed)
+}
+
+// Sign returns the signature of the message with the key, truncated to length bytes.
+func (k SignatureKey) Sign(message string, length int) string {
+	return internal.HMACSignature(k.Secret, message, length)
+}
+
+// SignatureVerifier checks that QR codes were signed with one of its keys.
+type SignatureVerifier struct {
+	// Keys are the accepted keys. When rotating keys, keep the previous key
+	// until the QR codes signed with it have expired.
+	Keys []SignatureKey
+
+	// Reference means signatures are stored as a suffix of the reference label
+	// (Tag 62.05) rather than in a template identified by SignatureGUID.
+	// Such signatures carry no key ID, so every key is tried.
+	Reference bool
+
+	// Length is the signature length in bytes (default: DefaultSignatureLength)
+	Length int
+}
+
+// VerifiedSignature describes a valid signature.
+type VerifiedSignature struct {
+	// KeyID is the ID of the key that signed the QR code
+	KeyID string
+
+	// Reference is the reference label (Tag 62.05) without a signature suffix
+	Reference string
+}
+
+// Verify checks the signature of a QR code against the payee, amount and reference.
+//
+// Returns ErrUnsigned if the QR code has no signature, ErrUnknownSignatureKey if
+// it is signed with a key ID the verifier does not have, and ErrSignatureMismatch
+// if the signature does not match, e.g. because the amount or payee was changed.
+func (v *SignatureVerifier) Verify(q *EMVCoQR) (*VerifiedSignature, error) {
+	length := v.Length
+	if length == 0 {
+		length = DefaultSignatureLength
+	}
+	if length < MinSignatureLength || length > MaxSignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d: must be %d-%d bytes", length, MinSignatureLength, MaxSignatureLength)
+	}
+
+	tags := q.GetTags()
+	label := q.ReferenceLabel()
+	if v.Reference {
+		if len(label) < 2*length {
+			return nil, ErrUnsigned
+		}
+		reference, signature := label[:len(label)-2*length], strings.ToUpper(label[len(label)-2*length:])
+		message := SignatureMessage(tags, reference)
+		for _, key := range v.Keys {
+			if len(key.Secret) > 0 && hmac.Equal([]byte(key.Sign(message, length)), []byte(signature)) {
+				return &VerifiedSignature{KeyID: key.ID, Reference: reference}, nil
+			}
+		}
+		return nil, ErrSignatureMismatch
+	}
+
+	for _, tag := range tags {
+		if tag.ID < "80" || tag.ID > "99" {
+			continue
+		}
+		sub, err := Decode(tag.Value)
+		if err != nil || subTagValue(sub, "00") != SignatureGUID {
+			continue
+		}
+		keyID, signature := subTagValue(sub, "01"), strings.ToUpper(subTagValue(sub, "02"))
+		for _, key := range v.Keys {
+			if key.ID != keyID || len(key.Secret) == 0 {
+				continue
+			}
+			if len(signature) != 2*length || !hmac.Equal([]byte(key.Sign(SignatureMessage(tags, label), length)), []byte(signature)) {
+				return nil, ErrSignatureMismatch
+			}
+			return &VerifiedSignature{KeyID: keyID, Reference: label}, nil
+		}
+		return nil, fmt.Errorf("%w: %q", ErrUnknownSignatureKey, keyID)
+	}
+	return nil, ErrUnsigned
+}
@@ -0,0 +1,116 @@
+package thaiqrgo
+
+import (
+	"errors"
+	"testing"
+)
+
+var (
+	signatureKeyOld = SignatureKey{ID: "K1", Secret: []byte("0123456789abcdef-old")}
+	signatureKeyNew = SignatureKey{ID: "K2", Secret: []byte("0123456789abcdef-new")}
+)
+
+// signedPayload builds an AnyID payload signed with key in a Tag 90 template,
+// or in the reference label if inReference is set.
+func signedPayload(key SignatureKey, proxy, amount string, inReference bool) string {
+	tags := []TLVTag{
+		Tag("00", "01"),
+		Tag("01", "12"),
+		Tag("29", Encode([]TLVTag{Tag("00", "A000000677010111"), Tag("01", proxy)})),
+		Tag("53", "764"),
+		Tag("58", "TH"),
+		Tag("54", amount),
+	}
+	signature := key.Sign(SignatureMessage(tags, "ORDER1"), DefaultSignatureLength)
+	if inReference {
+		tags = append(tags, Tag("62", Encode([]TLVTag{Tag("05", "ORDER1"+signature)})))
+	} else {
+		tags = append(tags,
+			Tag("62", Encode([]TLVTag{Tag("05", "ORDER1")})),
+			Tag("90", Encode([]TLVTag{Tag("00", SignatureGUID), Tag("01", key.ID), Tag("02", signature)})),
+		)
+	}
+	return WithCRCTag(Encode(tags), "63", true)
+}
+
+// resigned replaces a tag value and recomputes the CRC, as a forger would.
+func resigned(t *testing.T, payload, tagID, value string) string {
+	t.Helper()
+	tags, err := Decode(payload)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	var out []TLVTag
+	for _, tag := range tags {
+		switch tag.ID {
+		case "63":
+			continue
+		case tagID:
+			tag = Tag(tagID, value)
+		}
+		out = append(out, tag)
+	}
+	return WithCRCTag(Encode(out), "63", true)
+}
+
+func TestSignatureVerifier_Verify(t *testing.T) {
+	rotated := &SignatureVerifier{Keys: []SignatureKey{signatureKeyNew, signatureKeyOld}}
+	payload := signedPayload(signatureKeyOld, "0066812345678", "500.00", false)
+
+	tests := []struct {
+		name     string
+		verifier *SignatureVerifier
+		payload  string
+		wantKey  string
+		wantErr  error
+	}{
+		{"current key", rotated, signedPayload(signatureKeyNew, "0066812345678", "500.00", false), "K2", nil},
+		{"previous key", rotated, payload, "K1", nil},
+		{"retired key", &SignatureVerifier{Keys: []SignatureKey{signatureKeyNew}}, payload, "", ErrUnknownSignatureKey},
+		{"lowered amount", rotated, resigned(t, payload, "54", "5.00"), "", ErrSignatureMismatch},
+		{"swapped payee", rotated, resigned(t, payload, "29", Encode([]TLVTag{Tag("00", "A000000677010111"), Tag("01", "0066899999999")})), "", ErrSignatureMismatch},
+		{"changed reference", rotated, resigned(t, payload, "62", Encode([]TLVTag{Tag("05", "ORDER2")})), "", ErrSignatureMismatch},
+		{"truncated signature", rotated, resigned(t, payload, "90", Encode([]TLVTag{Tag("00", SignatureGUID), Tag("01", "K1"), Tag("02", "00")})), "", ErrSignatureMismatch},
+		{"unsigned", rotated, dynamicPayload("12", ""), "", ErrUnsigned},
+		{"reference", &SignatureVerifier{Keys: rotated.Keys, Reference: true}, signedPayload(signatureKeyOld, "0066812345678", "500.00", true), "K1", nil},
+		{"reference lowered amount", &SignatureVerifier{Keys: rotated.Keys, Reference: true}, resigned(t, signedPayload(signatureKeyOld, "0066812345678", "500.00", true), "54", "5.00"), "", ErrSignatureMismatch},
+		{"reference unsigned", &SignatureVerifier{Keys: rotated.Keys, Reference: true}, dynamicPayload("12", Encode([]TLVTag{Tag("05", "ORDER1")})), "", ErrUnsigned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qr, err := Parse(tt.payload, true, true)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			got, err := tt.verifier.Verify(qr)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.KeyID != tt.wantKey {
+				t.Errorf("Verify() KeyID = %v, want %v", got.KeyID, tt.wantKey)
+			}
+			if got.Reference != "ORDER1" {
+				t.Errorf("Verify() Reference = %v, want ORDER1", got.Reference)
+			}
+		})
+	}
+}
+
+func TestSignatureVerifier_Length(t *testing.T) {
+	qr, err := Parse(signedPayload(signatureKeyOld, "0066812345678", "500.00", false), true, true)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if _, err := (&SignatureVerifier{Keys: []SignatureKey{signatureKeyOld}, Length: 2}).Verify(qr); err == nil {
+		t.Error("Verify() with a 2 byte signature length should return error")
+	}
+	if _, err := (&SignatureVerifier{Keys: []SignatureKey{signatureKeyOld}, Length: 33}).Verify(qr); err == nil || errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("Verify() with a 33 byte signature length error = %v, want configuration error", err)
+	}
+	if _, err := (&SignatureVerifier{Keys: []SignatureKey{signatureKeyOld}, Length: 6}).Verify(qr); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("Verify() with another signature length error = %v, want %v", err, ErrSignatureMismatch)
+	}
+}
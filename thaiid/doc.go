@@ -0,0 +1,7 @@
+// Package thaiid validates Thai national ID and tax ID numbers.
+//
+// Citizen IDs and juristic person tax IDs share the same 13-digit format:
+// the last digit is a mod-11 check digit over the first 12, and the first
+// digit tells the kind of holder (0 for juristic persons, 1-8 for individuals).
+// PromptPay uses them as NATID proxies and as the prefix of biller IDs.
+package thaiid
@@ -0,0 +1,134 @@
+package thaiid
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Length is the number of digits of a national ID or tax ID.
+const Length = 13
+
+// Validation errors, usable with errors.Is.
+var (
+	ErrLength   = errors.New("invalid ID: must be 13 digits")
+	ErrDigits   = errors.New("invalid ID: must contain digits only")
+	ErrChecksum = errors.New("invalid ID: check digit mismatch")
+)
+
+// Kind is the kind of holder of an ID, given by its leading digit.
+type Kind int
+
+// Kinds of ID holders.
+const (
+	// KindUnknown is an ID with a leading digit that is not assigned (9)
+	KindUnknown Kind = iota
+
+	// KindJuristic is a juristic person or other tax payer registered with
+	// the Revenue Department (leading digit 0)
+	KindJuristic
+
+	// KindCitizen is a Thai national (leading digits 1-5)
+	KindCitizen
+
+	// KindResident is a non-Thai resident or their child born in Thailand
+	// (leading digits 6-8)
+	KindResident
+)
+
+// String implements the fmt.Stringer interface.
+func (k Kind) String() string {
+	switch k {
+	case KindJuristic:
+		return "juristic"
+	case KindCitizen:
+		return "citizen"
+	case KindResident:
+		return "resident"
+	}
+	return "unknown"
+}
+
+// CheckDigit calculates the check digit for the first 12 digits of an ID.
+//
+// Returns an error if digits is not 12 digits long.
+func CheckDigit(digits string) (byte, error) {
+	if len(digits) != Length-1 {
+		return 0, fmt.Errorf("invalid ID: check digit needs %d digits, got %d", Length-1, len(digits))
+	}
+	if !isDigits(digits) {
+		return 0, ErrDigits
+	}
+
+	sum := 0
+	for i := 0; i < Length-1; i++ {
+		sum += int(digits[i]-'0') * (Length - i)
+	}
+	return byte('0' + (11-sum%11)%10), nil
+}
+
+// Validate checks the length, digits and check digit of a 13-digit ID.
+//
+// Returns nil if the ID is valid, or one of ErrLength, ErrDigits and ErrChecksum.
+func Validate(id string) error {
+	if len(id) != Length {
+		return ErrLength
+	}
+	if !isDigits(id) {
+		return ErrDigits
+	}
+
+	check, _ := CheckDigit(id[:Length-1])
+	if id[Length-1] != check {
+		return ErrChecksum
+	}
+	return nil
+}
+
+// Valid reports whether id is a valid 13-digit ID.
+func Valid(id string) bool {
+	return Validate(id) == nil
+}
+
+// Classify returns the kind of holder of an ID from its leading digit.
+//
+// It does not validate the ID; use Validate for that.
+func Classify(id string) Kind {
+	if id == "" {
+		return KindUnknown
+	}
+	switch c := id[0]; {
+	case c == '0':
+		return KindJuristic
+	case c >= '1' && c <= '5':
+		return KindCitizen
+	case c >= '6' && c <= '8':
+		return KindResident
+	}
+	return KindUnknown
+}
+
+// ValidateBillerID checks a PromptPay biller ID: a 13-digit tax ID
+// optionally followed by a 2-digit suffix.
+//
+// Returns nil if the tax ID prefix is valid.
+func ValidateBillerID(billerID string) error {
+	switch len(billerID) {
+	case Length:
+		return Validate(billerID)
+	case Length + 2:
+		if !isDigits(billerID[Length:]) {
+			return ErrDigits
+		}
+		return Validate(billerID[:Length])
+	}
+	return fmt.Errorf("invalid biller ID: must be 13 or 15 digits, got %d", len(billerID))
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
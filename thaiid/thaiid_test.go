@@ -0,0 +1,102 @@
+package thaiid
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		digits string
+		want   byte
+	}{
+		{"099400016550", '1'},
+		{"111111111111", '9'},
+		{"310100123456", '5'},
+		{"000000000000", '1'},
+	}
+
+	for _, tt := range tests {
+		got, err := CheckDigit(tt.digits)
+		if err != nil {
+			t.Fatalf("CheckDigit(%q) error = %v", tt.digits, err)
+		}
+		if got != tt.want {
+			t.Errorf("CheckDigit(%q) = %c, want %c", tt.digits, got, tt.want)
+		}
+	}
+
+	if _, err := CheckDigit("12345"); err == nil {
+		t.Error("CheckDigit() should return error for short input")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		id   string
+		want error
+	}{
+		{"0994000165501", nil},
+		{"1111111111119", nil},
+		{"3101001234565", nil},
+		{"0994000165502", ErrChecksum},
+		{"1111111111111", ErrChecksum},
+		{"111111111111", ErrLength},
+		{"11111111111119", ErrLength},
+		{"", ErrLength},
+		{"1-1111-11111-1", ErrLength},
+		{"111111111111A", ErrDigits},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			if got := Validate(tt.id); !errors.Is(got, tt.want) {
+				t.Errorf("Validate(%q) = %v, want %v", tt.id, got, tt.want)
+			}
+			if got := Valid(tt.id); got != (tt.want == nil) {
+				t.Errorf("Valid(%q) = %v, want %v", tt.id, got, tt.want == nil)
+			}
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		id   string
+		want Kind
+	}{
+		{"0994000165501", KindJuristic},
+		{"1111111111119", KindCitizen},
+		{"5111111111111", KindCitizen},
+		{"6111111111111", KindResident},
+		{"8111111111111", KindResident},
+		{"9111111111111", KindUnknown},
+		{"", KindUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := Classify(tt.id); got != tt.want {
+			t.Errorf("Classify(%q) = %v, want %v", tt.id, got, tt.want)
+		}
+	}
+}
+
+func TestValidateBillerID(t *testing.T) {
+	tests := []struct {
+		billerID string
+		wantErr  bool
+	}{
+		{"099400016550100", false},
+		{"0994000165501", false},
+		{"099400016550200", true},
+		{"09940001655011X", true},
+		{"09940001655010", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		if err := ValidateBillerID(tt.billerID); (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBillerID(%q) error = %v, wantErr %v", tt.billerID, err, tt.wantErr)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/klimakov/thai-qr-go/internal"
 )
@@ -23,30 +24,55 @@ type TLVTag struct {
 	Length int
 }
 
+// LengthMode selects how TLV length fields are counted.
+type LengthMode int
+
+const (
+	// LengthBytes counts lengths in bytes (default).
+	LengthBytes LengthMode = iota
+
+	// LengthRunes counts lengths in Unicode characters, as EMVCo specifies
+	// for fields that may contain non-ASCII text (e.g. Tag 64).
+	LengthRunes
+)
+
 // Decode decodes a TLV string into an array of TLV tags.
 //
 // Returns an error if the payload format is invalid (e.g., incomplete tags).
 func Decode(payload string) ([]TLVTag, error) {
+	tags, _, err := decodeTLV(payload, LengthBytes)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// decodeTLV decodes a TLV string using the given length mode.
+//
+// On error it also returns the tags decoded so far and the byte position
+// where decoding stopped.
+func decodeTLV(payload string, mode LengthMode) ([]TLVTag, int, error) {
 	var tags []TLVTag
 
 	idx := 0
 	for idx < len(payload) {
 		if idx+4 > len(payload) {
-			return nil, fmt.Errorf("invalid TLV format: incomplete tag header at position %d", idx)
+			return tags, idx, fmt.Errorf("invalid TLV format: incomplete tag header at position %d", idx)
 		}
 
 		id := payload[idx : idx+2]
 		lengthStr := payload[idx+2 : idx+4]
 		length, err := strconv.Atoi(lengthStr)
 		if err != nil {
-			return nil, fmt.Errorf("invalid TLV format: invalid length at position %d: %w", idx+2, err)
+			return tags, idx + 2, fmt.Errorf("invalid TLV format: invalid length at position %d: %w", idx+2, err)
 		}
 
-		if idx+4+length > len(payload) {
-			return nil, fmt.Errorf("invalid TLV format: incomplete tag value at position %d (expected %d bytes, got %d)", idx+4, length, len(payload)-idx-4)
+		end := valueEnd(payload, idx+4, length, mode)
+		if end < 0 {
+			return tags, idx + 4, fmt.Errorf("invalid TLV format: incomplete tag value at position %d (expected %d bytes, got %d)", idx+4, length, len(payload)-idx-4)
 		}
 
-		value := payload[idx+4 : idx+4+length]
+		value := payload[idx+4 : end]
 
 		tags = append(tags, TLVTag{
 			ID:     id,
@@ -54,10 +80,31 @@ func Decode(payload string) ([]TLVTag, error) {
 			Value:  value,
 		})
 
-		idx += 4 + length
+		idx = end
 	}
 
-	return tags, nil
+	return tags, idx, nil
+}
+
+// valueEnd returns the byte position where a value of the given length
+// starting at start ends, or -1 if the payload is too short.
+func valueEnd(payload string, start, length int, mode LengthMode) int {
+	if mode != LengthRunes {
+		if start+length > len(payload) {
+			return -1
+		}
+		return start + length
+	}
+
+	end := start
+	for range length {
+		if end >= len(payload) {
+			return -1
+		}
+		_, size := utf8.DecodeRuneInString(payload[end:])
+		end += size
+	}
+	return end
 }
 
 // Encode encodes an array of TLV tags into a TLV string.
@@ -2,12 +2,15 @@ package validate
 
 import (
 	"errors"
+	"fmt"
+
 	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/bank"
 )
 
 // SlipVerifyData contains extracted data from a Slip Verify QR code.
 type SlipVerifyData struct {
-	// SendingBank is the bank code
+	// SendingBank is the bank code (see bank.Lookup)
 	SendingBank string
 
 	// TransRef is the transaction reference
@@ -18,6 +21,7 @@ type SlipVerifyData struct {
 //
 // This function is used with Bank Open API to extract bank code and transaction reference.
 // Returns an error if the payload is invalid or doesn't match the Slip Verify format.
+// A sending bank that is not in the bank registry is reported with bank.ErrUnknownCode.
 func SlipVerify(payload string) (*SlipVerifyData, error) {
 	ppqr, err := thaiqrgo.Parse(payload, true, true)
 	if err != nil {
@@ -32,6 +36,10 @@ func SlipVerify(payload string) (*SlipVerifyData, error) {
 		return nil, errors.New("invalid Slip Verify format: missing required fields")
 	}
 
+	if _, ok := bank.Lookup(sendingBank); !ok {
+		return nil, fmt.Errorf("invalid Slip Verify format: %w: %q", bank.ErrUnknownCode, sendingBank)
+	}
+
 	return &SlipVerifyData{
 		SendingBank: sendingBank,
 		TransRef:    transRef,
@@ -1,8 +1,11 @@
 package validate
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/klimakov/thai-qr-go"
+	"github.com/klimakov/thai-qr-go/bank"
 	"github.com/klimakov/thai-qr-go/generate"
 )
 
@@ -104,3 +107,12 @@ func TestTrueMoneySlipVerify_Invalid(t *testing.T) {
 		t.Error("TrueMoneySlipVerify() should return error for empty payload")
 	}
 }
+
+func TestSlipVerify_UnknownBank(t *testing.T) {
+	// Sending bank 999 is not in the bank registry
+	payload := thaiqrgo.WithCRCTag("004100060000010103999022000111222233344ABCD125102TH", "91", true)
+	_, err := SlipVerify(payload)
+	if !errors.Is(err, bank.ErrUnknownCode) {
+		t.Errorf("SlipVerify() error = %v, want %v", err, bank.ErrUnknownCode)
+	}
+}